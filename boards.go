@@ -9,6 +9,7 @@
 package datadog
 
 import (
+	"encoding/json"
 	"fmt"
 )
 
@@ -41,6 +42,27 @@ type Board struct {
 	Url                     *string                  `json:"url,omitempty"`
 	CreatedAt               *string                  `json:"created_at,omitempty"`
 	ModifiedAt              *string                  `json:"modified_at,omitempty"`
+
+	// unknown holds the fields this library does not know, in lossless mode.
+	unknown map[string]json.RawMessage
+}
+
+func (b *Board) setUnknownFields(unknown map[string]json.RawMessage) {
+	b.unknown = unknown
+}
+
+func (b *Board) readOnlyFields() []string {
+	return []string{"author_handle", "author_name", "created_at", "modified_at", "deleted_at", "url"}
+}
+
+// MarshalJSON encodes the board, including the fields kept in lossless mode.
+func (b Board) MarshalJSON() ([]byte, error) {
+	type board Board
+	data, err := json.Marshal(board(b))
+	if err != nil {
+		return nil, err
+	}
+	return addUnknownFields(data, b.unknown)
 }
 
 // BoardLite represents a simplify dashboard (without widgets, notify list, ...)
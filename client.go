@@ -9,6 +9,7 @@
 package datadog
 
 import (
+	"context"
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
@@ -21,19 +22,67 @@ import (
 // state information for a particular application connection.
 type Client struct {
 	apiKey, appKey, baseUrl string
+	// credentials, when set, provides the keys instead of apiKey and appKey.
+	credentials CredentialsProvider
+	// Mutex to protect the keys and their provider.
+	keysMutex sync.RWMutex
+
+	// AuthMode tells where the keys of requests go. It is AuthModeLegacy by
+	// default, which puts them in the query string for a few endpoints.
+	AuthMode AuthMode
 
 	//The Http Client that is used to make requests
-	HttpClient   *http.Client
+	HttpClient *http.Client
+	// RetryTimeout is how long failed requests are retried for. They are
+	// retried with no time limit if it is 0.
 	RetryTimeout time.Duration
+	// RetryPolicy decides which failed requests are retried and how long to
+	// wait between attempts. DefaultRetryPolicy is used when it is nil.
+	RetryPolicy RetryPolicy
 
 	//Option to specify extra headers like User-Agent
 	ExtraHeader map[string]string
 
+	// Compression compresses the bodies of the requests to the endpoints
+	// accepting a Content-Encoding header, i.e. the series and distribution
+	// points intake; the bodies of other requests are sent as is.
+	// EndpointCompression sets the compression of the endpoints it lists by
+	// path, e.g. "/v1/series", whether they are intake endpoints or not.
+	Compression         Compression
+	EndpointCompression map[string]Compression
+
+	// middlewares wrap the sending of every request, see Use.
+	middlewares []Middleware
+
+	// Logger, when set, records every request sent by the client.
+	Logger Logger
+	// Debug makes Logger record requests and responses in full, bodies
+	// included, with keys redacted.
+	Debug bool
+
+	// RateLimiting makes the client throttle itself once the rate limit of an
+	// endpoint is used up. It is RateLimitOff by default.
+	RateLimiting RateLimitMode
+
+	// Lossless makes resources such as Monitor and Board keep the JSON fields
+	// this library does not know when they are received, and send them back
+	// when the resource is updated. Fields set by the API, such as creation
+	// times, are not kept.
+	Lossless bool
+
 	// rateLimiting is used to store the rate limitting stats.
 	// More information in the official documentation: https://docs.datadoghq.com/api/?lang=bash#rate-limiting
 	rateLimitingStats map[string]RateLimit
-	// Mutex to protect the rate limiting map.
+	// rateLimitWindows tracks the requests left per endpoint for RateLimiting.
+	rateLimitWindows map[string]*rateLimitWindow
+	// Mutex to protect the rate limiting maps.
 	m sync.Mutex
+
+	// ctx is the context requests are bound to, set by WithContext.
+	ctx context.Context
+	// parent is the client a WithContext view was derived from. Views share
+	// its rate limiting stats.
+	parent *Client
 }
 
 type RateLimit struct {
@@ -52,26 +101,99 @@ type valid struct {
 // NewClient returns a new datadog.Client which can be used to access the API
 // methods. The expected argument is the API key.
 func NewClient(apiKey, appKey string) *Client {
-	baseUrl := os.Getenv("DATADOG_HOST")
-	if baseUrl == "" {
-		baseUrl = "https://api.datadoghq.com"
+	options := []ClientOption{WithKeys(apiKey, appKey)}
+	if baseUrl := os.Getenv("DATADOG_HOST"); baseUrl != "" {
+		options = append(options, WithBaseUrl(baseUrl))
 	}
+	return NewClientWithOptions(options...)
+}
 
-	return &Client{
-		apiKey:            apiKey,
-		appKey:            appKey,
-		baseUrl:           baseUrl,
+// ClientOption configures a Client built by NewClientWithOptions.
+type ClientOption func(*Client)
+
+// NewClientWithOptions returns a new datadog.Client configured by options. It
+// talks to the US1 site unless told otherwise, and unlike NewClient it does not
+// look at the environment.
+func NewClientWithOptions(options ...ClientOption) *Client {
+	client := &Client{
+		baseUrl:           NewSite(SiteUS1).APIURL,
 		HttpClient:        http.DefaultClient,
 		RetryTimeout:      time.Duration(60 * time.Second),
+		RetryPolicy:       NewDefaultRetryPolicy(),
 		rateLimitingStats: make(map[string]RateLimit),
 		ExtraHeader:       make(map[string]string),
 	}
+	for _, option := range options {
+		option(client)
+	}
+	return client
+}
+
+// WithKeys sets the API and application keys of the client.
+func WithKeys(apiKey, appKey string) ClientOption {
+	return func(c *Client) {
+		c.SetKeys(apiKey, appKey)
+	}
+}
+
+// WithSite makes the client talk to the Datadog site with the given name,
+// e.g. SiteEU1 or "us3.datadoghq.com".
+func WithSite(name string) ClientOption {
+	return WithBaseUrl(NewSite(name).APIURL)
+}
+
+// WithBaseUrl makes the client talk to the API at baseUrl, e.g. through a
+// proxy. The Datadog site is found from it when needed.
+func WithBaseUrl(baseUrl string) ClientOption {
+	return func(c *Client) {
+		c.SetBaseUrl(baseUrl)
+	}
+}
+
+// WithHTTPClient sets the HTTP client requests are sent with.
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *Client) {
+		c.HttpClient = httpClient
+	}
+}
+
+// WithRetryTimeout sets how long failed requests are retried for, with no
+// time limit if timeout is 0.
+func WithRetryTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) {
+		c.RetryTimeout = timeout
+	}
 }
 
-// SetKeys changes the value of apiKey and appKey.
+// WithRetryPolicy sets which failed requests are retried and how.
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(c *Client) {
+		c.RetryPolicy = policy
+	}
+}
+
+// WithUserAgent sets the User-Agent header of requests.
+func WithUserAgent(userAgent string) ClientOption {
+	return func(c *Client) {
+		c.ExtraHeader["User-Agent"] = userAgent
+	}
+}
+
+// WithMiddlewares adds middlewares to the client, see Use.
+func WithMiddlewares(middlewares ...Middleware) ClientOption {
+	return func(c *Client) {
+		c.Use(middlewares...)
+	}
+}
+
+// SetKeys changes the value of apiKey and appKey, replacing any credentials
+// provider. It is safe to call while requests are in flight.
 func (c *Client) SetKeys(apiKey, appKey string) {
+	c.keysMutex.Lock()
+	defer c.keysMutex.Unlock()
 	c.apiKey = apiKey
 	c.appKey = appKey
+	c.credentials = nil
 }
 
 // SetBaseUrl changes the value of baseUrl.
@@ -84,28 +206,84 @@ func (c *Client) GetBaseUrl() string {
 	return c.baseUrl
 }
 
+// WithContext returns a shallow copy of the client whose requests are bound to
+// ctx. Cancelling ctx, or reaching its deadline, aborts in-flight requests and
+// stops any pending retries. The copy shares its rate limiting stats with the
+// client it was derived from; changes made with SetKeys, SetCredentialsProvider
+// or SetBaseUrl on either one are not seen by the other.
+func (client *Client) WithContext(ctx context.Context) *Client {
+	if ctx == nil {
+		panic("nil context")
+	}
+	client.keysMutex.RLock()
+	defer client.keysMutex.RUnlock()
+	return &Client{
+		apiKey:       client.apiKey,
+		appKey:       client.appKey,
+		credentials:  client.credentials,
+		AuthMode:     client.AuthMode,
+		baseUrl:      client.baseUrl,
+		HttpClient:   client.HttpClient,
+		RetryTimeout: client.RetryTimeout,
+		RetryPolicy:  client.RetryPolicy,
+		ExtraHeader:  client.ExtraHeader,
+		Compression:  client.Compression,
+		RateLimiting: client.RateLimiting,
+		Lossless:     client.Lossless,
+		Logger:       client.Logger,
+		Debug:        client.Debug,
+		ctx:          ctx,
+		parent:       client.root(),
+		// Middlewares added to the copy are not added to the client.
+		middlewares:         client.middlewares[:len(client.middlewares):len(client.middlewares)],
+		EndpointCompression: client.EndpointCompression,
+	}
+}
+
+// Context returns the context requests made by the client are bound to. It
+// is context.Background() unless the client was obtained with WithContext.
+func (client *Client) Context() context.Context {
+	if client.ctx != nil {
+		return client.ctx
+	}
+	return context.Background()
+}
+
+// root returns the client owning the state shared with WithContext views.
+func (client *Client) root() *Client {
+	if client.parent != nil {
+		return client.parent
+	}
+	return client
+}
+
 // Validate checks if the API key (not the APP key) is valid.
 func (client *Client) Validate() (bool, error) {
 	var out valid
 	var resp *http.Response
 
-	uri, err := client.uriForAPI("/v1/validate")
+	keys, err := client.keys()
 	if err != nil {
 		return false, err
 	}
+	uri, err := client.uriForAPI("/v1/validate", keys)
+	if err != nil {
+		return false, keys.redactError(err)
+	}
 
 	req, err := http.NewRequest("GET", uri, nil)
 	if err != nil {
-		return false, err
+		return false, keys.redactError(err)
 	}
-	req.Header.Set("DD-API-KEY", client.apiKey)
-	if (client.appKey != "") {
-		req.Header.Set("DD-APPLICATION-KEY", client.appKey)
+	req = req.WithContext(client.Context())
+	req.Header.Set("DD-API-KEY", keys.APIKey)
+	if keys.AppKey != "" {
+		req.Header.Set("DD-APPLICATION-KEY", keys.AppKey)
 	}
 
-	resp, err = client.doRequestWithRetries(req, client.RetryTimeout)
+	resp, err = client.doRequestWithRetries(req, keys, client.RetryTimeout)
 	if err != nil {
-		return false, err
+		return false, keys.redactError(err)
 	}
 
 	defer resp.Body.Close()
@@ -0,0 +1,44 @@
+package datadog
+
+import (
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewClientWithOptions(t *testing.T) {
+	t.Run("Defaults", func(t *testing.T) {
+		c := NewClientWithOptions()
+		assert.Equal(t, "https://api.datadoghq.com", c.GetBaseUrl())
+		assert.Equal(t, http.DefaultClient, c.HttpClient)
+		assert.Equal(t, 60*time.Second, c.RetryTimeout)
+	})
+	t.Run("Options", func(t *testing.T) {
+		httpClient := &http.Client{}
+		c := NewClientWithOptions(
+			WithKeys("sample_api_key", "sample_app_key"),
+			WithSite(SiteUS3),
+			WithHTTPClient(httpClient),
+			WithRetryTimeout(time.Second),
+			WithUserAgent("my-agent/1.0"),
+		)
+		assert.Equal(t, "https://api.us3.datadoghq.com", c.GetBaseUrl())
+		assert.Equal(t, httpClient, c.HttpClient)
+		assert.Equal(t, time.Second, c.RetryTimeout)
+
+		req, _, err := c.createRequest("GET", "/v1/dashboard", nil)
+		assert.Nil(t, err)
+		assert.Equal(t, "https://api.us3.datadoghq.com/api/v1/dashboard", req.URL.String())
+		assert.Equal(t, "my-agent/1.0", req.Header.Get("User-Agent"))
+		assert.Equal(t, "sample_api_key", req.Header.Get("DD-API-KEY"))
+	})
+	t.Run("NewClient reads DATADOG_HOST", func(t *testing.T) {
+		defer os.Setenv("DATADOG_HOST", os.Getenv("DATADOG_HOST"))
+		os.Setenv("DATADOG_HOST", "https://api.datadoghq.eu")
+		c := NewClient("sample_api_key", "sample_app_key")
+		assert.Equal(t, "https://api.datadoghq.eu", c.GetBaseUrl())
+	})
+}
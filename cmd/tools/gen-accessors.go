@@ -108,6 +108,11 @@ func (t *templateData) processAST(f *ast.File) error {
 				// TODO: handle more than one types, is that a thing?
 				fieldName := field.Names[0]
 
+				// Unexported fields are internal state, not part of the API
+				if !fieldName.IsExported() {
+					continue
+				}
+
 				switch x := se.X.(type) {
 				// An array or slice type
 				case *ast.ArrayType:
@@ -185,7 +190,7 @@ func (t *templateData) addIdent(x *ast.Ident, receiverType, fieldName string) {
 		zeroValue = "0"
 	case "Status":
 		zeroValue = "0"
-	case "PrecisionT":
+	case "PrecisionT", "MonitorStatus":
 		zeroValue = `""`
 	default:
 		zeroValue = fmt.Sprintf("%s{}", x.String())
@@ -0,0 +1,92 @@
+/*
+ * Datadog API for Go
+ *
+ * Please see the included LICENSE file for licensing information.
+ *
+ * Copyright 2020 by authors and contributors.
+ */
+
+package datadog
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Compression is the encoding of request bodies.
+type Compression string
+
+// The supported request body encodings.
+const (
+	CompressionNone    Compression = ""
+	CompressionGzip    Compression = "gzip"
+	CompressionDeflate Compression = "deflate"
+)
+
+// WithCompression compresses the bodies of the requests to the endpoints
+// accepting it, see Client.Compression.
+func WithCompression(compression Compression) ClientOption {
+	return func(c *Client) {
+		c.Compression = compression
+	}
+}
+
+// WithEndpointCompression sets the compression of the requests to one
+// endpoint, e.g. "/v1/series", see Client.EndpointCompression.
+func WithEndpointCompression(api string, compression Compression) ClientOption {
+	return func(c *Client) {
+		if c.EndpointCompression == nil {
+			c.EndpointCompression = make(map[string]Compression)
+		}
+		c.EndpointCompression[api] = compression
+	}
+}
+
+// compressedEndpoints are the endpoints accepting compressed bodies, which
+// Client.Compression applies to.
+var compressedEndpoints = []string{"/v1/series", "/v1/distribution_points"}
+
+// compression returns the compression of the requests to api.
+func (client *Client) compression(api string) Compression {
+	path := api
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path = path[:i]
+	}
+	if compression, ok := client.EndpointCompression[path]; ok {
+		return compression
+	}
+	for _, endpoint := range compressedEndpoints {
+		if path == endpoint {
+			return client.Compression
+		}
+	}
+	return CompressionNone
+}
+
+// compress encodes body with compression.
+func compress(body []byte, compression Compression) ([]byte, error) {
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	switch compression {
+	case CompressionNone:
+		return body, nil
+	case CompressionGzip:
+		w = gzip.NewWriter(&buf)
+	case CompressionDeflate:
+		// The deflate content encoding is zlib framed, see RFC 7230.
+		w = zlib.NewWriter(&buf)
+	default:
+		return nil, fmt.Errorf("unsupported compression %q", compression)
+	}
+	if _, err := w.Write(body); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
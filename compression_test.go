@@ -0,0 +1,88 @@
+package datadog_test
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	dd "github.com/zorkian/go-datadog-api"
+)
+
+func TestRequestCompression(t *testing.T) {
+	var bodies []string
+	var encodings []string
+	failures := 1
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encoding := r.Header.Get("Content-Encoding")
+		encodings = append(encodings, encoding)
+		var reader io.Reader = r.Body
+		switch encoding {
+		case "gzip":
+			gz, err := gzip.NewReader(r.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			reader = gz
+		case "deflate":
+			zr, err := zlib.NewReader(r.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			reader = zr
+		}
+		body, err := ioutil.ReadAll(reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		bodies = append(bodies, string(body))
+
+		if r.URL.Path == "/api/v1/series" && failures > 0 {
+			failures--
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"status": "ok"}`))
+	}))
+	defer ts.Close()
+
+	client := dd.NewClientWithOptions(
+		dd.WithKeys("foo", "bar"),
+		dd.WithBaseUrl(ts.URL),
+		dd.WithRetryPolicy(&dd.DefaultRetryPolicy{InitialInterval: time.Millisecond, MaxInterval: time.Millisecond, Multiplier: 1}),
+		dd.WithEndpointCompression("/v1/series", dd.CompressionGzip),
+	)
+
+	// The compressed body is sent again when the request is retried.
+	metrics := []dd.Metric{{Metric: dd.String("foo"), Points: []dd.DataPoint{{dd.Float64(1), dd.Float64(2)}}}}
+	assert.Nil(t, client.PostMetrics(metrics))
+	assert.Equal(t, []string{"gzip", "gzip"}, encodings)
+	want := `{"series":[{"metric":"foo","points":[[1,2]]}]}`
+	assert.Equal(t, []string{want, want}, bodies)
+
+	// The compression of the client only applies to the intake endpoints
+	// accepting compressed bodies.
+	encodings, bodies = nil, nil
+	client.Compression = dd.CompressionDeflate
+	distributions := []dd.DistributionSeries{{Metric: dd.String("foo"),
+		Points: []dd.DistributionPoint{{Timestamp: 1, Values: []float64{2}}}}}
+	assert.Nil(t, client.PostDistributionPoints(distributions))
+	_, err := client.PostEvent(&dd.Event{Title: dd.String("foo")})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"deflate", ""}, encodings)
+
+	// Other endpoints are compressed when listed explicitly.
+	encodings, bodies = nil, nil
+	client.EndpointCompression["/v1/events"] = dd.CompressionGzip
+	_, err = client.PostEvent(&dd.Event{Title: dd.String("foo")})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"gzip"}, encodings)
+
+	client.Compression = "brotli"
+	assert.NotNil(t, client.PostDistributionPoints(distributions))
+}
@@ -0,0 +1,187 @@
+/*
+ * Datadog API for Go
+ *
+ * Please see the included LICENSE file for licensing information.
+ *
+ * Copyright 2020 by authors and contributors.
+ */
+
+package datadog
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AuthMode tells the client where to put the keys of requests.
+type AuthMode int
+
+const (
+	// AuthModeLegacy sends keys in headers, except for the metric, check run,
+	// event and screenboard endpoints which get them in the query string.
+	AuthModeLegacy AuthMode = iota
+	// AuthModeHeaders sends keys in headers for every endpoint, so that they
+	// never show up in proxy logs or errors.
+	AuthModeHeaders
+	// AuthModeStrict is AuthModeHeaders, with requests whose URL holds keys
+	// anyway, e.g. a full URL given by the caller, refused with an error.
+	AuthModeStrict
+)
+
+// WithAuthMode sets where the client puts the keys of requests.
+func WithAuthMode(mode AuthMode) ClientOption {
+	return func(c *Client) {
+		c.AuthMode = mode
+	}
+}
+
+// Credentials are the keys requests to the Datadog API are authenticated
+// with.
+type Credentials struct {
+	APIKey string `json:"api_key"`
+	AppKey string `json:"app_key"`
+}
+
+// CredentialsProvider provides the credentials of a client. It is asked for
+// them before every request, so that keys can be rotated without restarting,
+// and must be safe for concurrent use.
+type CredentialsProvider interface {
+	Credentials() (Credentials, error)
+}
+
+// staticCredentials always provides the same credentials.
+type staticCredentials Credentials
+
+// StaticCredentials returns a provider of fixed credentials.
+func StaticCredentials(apiKey, appKey string) CredentialsProvider {
+	return staticCredentials{APIKey: apiKey, AppKey: appKey}
+}
+
+func (c staticCredentials) Credentials() (Credentials, error) {
+	return Credentials(c), nil
+}
+
+// EnvCredentials provides credentials read from environment variables every
+// time they are asked for.
+type EnvCredentials struct {
+	APIKeyVar string
+	AppKeyVar string
+}
+
+// NewEnvCredentials returns a provider reading the DATADOG_API_KEY and
+// DATADOG_APP_KEY environment variables.
+func NewEnvCredentials() *EnvCredentials {
+	return &EnvCredentials{
+		APIKeyVar: "DATADOG_API_KEY",
+		AppKeyVar: "DATADOG_APP_KEY",
+	}
+}
+
+// Credentials implements CredentialsProvider.
+func (e *EnvCredentials) Credentials() (Credentials, error) {
+	apiKey := os.Getenv(e.APIKeyVar)
+	if apiKey == "" {
+		return Credentials{}, fmt.Errorf("environment variable %s is not set", e.APIKeyVar)
+	}
+	return Credentials{APIKey: apiKey, AppKey: os.Getenv(e.AppKeyVar)}, nil
+}
+
+// FileCredentials provides credentials read from a JSON file such as
+// {"api_key": "...", "app_key": "..."}. The file is read again whenever it
+// changes.
+type FileCredentials struct {
+	path string
+
+	// Mutex to protect the cached credentials.
+	m           sync.Mutex
+	credentials Credentials
+	modTime     time.Time
+	size        int64
+}
+
+// NewFileCredentials returns a provider reading the file at path.
+func NewFileCredentials(path string) *FileCredentials {
+	return &FileCredentials{path: path}
+}
+
+// Credentials implements CredentialsProvider.
+func (f *FileCredentials) Credentials() (Credentials, error) {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	f.m.Lock()
+	defer f.m.Unlock()
+	if f.credentials.APIKey != "" && info.ModTime().Equal(f.modTime) && info.Size() == f.size {
+		return f.credentials, nil
+	}
+
+	data, err := ioutil.ReadFile(f.path)
+	if err != nil {
+		return Credentials{}, err
+	}
+	var credentials Credentials
+	if err := json.Unmarshal(data, &credentials); err != nil {
+		return Credentials{}, fmt.Errorf("malformed credentials file %s: %s", f.path, err)
+	}
+	if credentials.APIKey == "" {
+		return Credentials{}, fmt.Errorf("no api_key in credentials file %s", f.path)
+	}
+
+	f.credentials = credentials
+	f.modTime = info.ModTime()
+	f.size = info.Size()
+	return credentials, nil
+}
+
+// ChainCredentials provides the credentials of the first of its providers
+// that has some.
+type ChainCredentials []CredentialsProvider
+
+// Credentials implements CredentialsProvider.
+func (c ChainCredentials) Credentials() (Credentials, error) {
+	var errs []string
+	for _, provider := range c {
+		credentials, err := provider.Credentials()
+		if err == nil {
+			return credentials, nil
+		}
+		errs = append(errs, err.Error())
+	}
+	return Credentials{}, fmt.Errorf("no credentials found: %s", strings.Join(errs, "; "))
+}
+
+// SetCredentialsProvider makes the client ask provider for its keys before
+// every request, instead of using the ones given to NewClient or SetKeys. It is
+// safe to call while requests are in flight.
+func (client *Client) SetCredentialsProvider(provider CredentialsProvider) {
+	client.keysMutex.Lock()
+	defer client.keysMutex.Unlock()
+	client.credentials = provider
+}
+
+// WithCredentialsProvider sets the credentials provider of the client.
+func WithCredentialsProvider(provider CredentialsProvider) ClientOption {
+	return func(c *Client) {
+		c.SetCredentialsProvider(provider)
+	}
+}
+
+// keys returns the credentials to authenticate a request with.
+func (client *Client) keys() (Credentials, error) {
+	client.keysMutex.RLock()
+	provider := client.credentials
+	credentials := Credentials{APIKey: client.apiKey, AppKey: client.appKey}
+	client.keysMutex.RUnlock()
+
+	if provider != nil {
+		return provider.Credentials()
+	}
+	return credentials, nil
+}
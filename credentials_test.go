@@ -0,0 +1,172 @@
+package datadog
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnvCredentials(t *testing.T) {
+	provider := &EnvCredentials{APIKeyVar: "TEST_DD_API_KEY", AppKeyVar: "TEST_DD_APP_KEY"}
+	defer os.Unsetenv("TEST_DD_API_KEY")
+	defer os.Unsetenv("TEST_DD_APP_KEY")
+
+	_, err := provider.Credentials()
+	assert.NotNil(t, err)
+
+	os.Setenv("TEST_DD_API_KEY", "env_api_key")
+	os.Setenv("TEST_DD_APP_KEY", "env_app_key")
+	credentials, err := provider.Credentials()
+	assert.Nil(t, err)
+	assert.Equal(t, Credentials{APIKey: "env_api_key", AppKey: "env_app_key"}, credentials)
+}
+
+func TestFileCredentials(t *testing.T) {
+	dir, err := ioutil.TempDir("", "credentials")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "keys.json")
+	provider := NewFileCredentials(path)
+
+	t.Run("Missing file", func(t *testing.T) {
+		_, err := provider.Credentials()
+		assert.NotNil(t, err)
+	})
+	t.Run("File is read", func(t *testing.T) {
+		assert.Nil(t, ioutil.WriteFile(path, []byte(`{"api_key": "api_1", "app_key": "app_1"}`), 0600))
+		credentials, err := provider.Credentials()
+		assert.Nil(t, err)
+		assert.Equal(t, Credentials{APIKey: "api_1", AppKey: "app_1"}, credentials)
+	})
+	t.Run("File is read again when it changes", func(t *testing.T) {
+		assert.Nil(t, ioutil.WriteFile(path, []byte(`{"api_key": "api_2", "app_key": "app_2"}`), 0600))
+		later := time.Now().Add(time.Minute)
+		assert.Nil(t, os.Chtimes(path, later, later))
+		credentials, err := provider.Credentials()
+		assert.Nil(t, err)
+		assert.Equal(t, Credentials{APIKey: "api_2", AppKey: "app_2"}, credentials)
+	})
+	t.Run("Malformed file", func(t *testing.T) {
+		assert.Nil(t, ioutil.WriteFile(path, []byte(`{"app_key": "app_3"}`), 0600))
+		_, err := provider.Credentials()
+		assert.NotNil(t, err)
+	})
+}
+
+func TestChainCredentials(t *testing.T) {
+	provider := ChainCredentials{
+		&EnvCredentials{APIKeyVar: "TEST_DD_UNSET_API_KEY"},
+		StaticCredentials("static_api_key", "static_app_key"),
+	}
+	credentials, err := provider.Credentials()
+	assert.Nil(t, err)
+	assert.Equal(t, Credentials{APIKey: "static_api_key", AppKey: "static_app_key"}, credentials)
+
+	_, err = ChainCredentials{&EnvCredentials{APIKeyVar: "TEST_DD_UNSET_API_KEY"}}.Credentials()
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "TEST_DD_UNSET_API_KEY")
+	}
+}
+
+func TestClientCredentialsProvider(t *testing.T) {
+	c := NewClientWithOptions(
+		WithKeys("sample_api_key", "sample_app_key"),
+		WithCredentialsProvider(StaticCredentials("provided_api_key", "provided_app_key")),
+	)
+
+	req, _, err := c.createRequest("GET", "/v1/dashboard", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "provided_api_key", req.Header.Get("DD-API-KEY"))
+	assert.Equal(t, "provided_app_key", req.Header.Get("DD-APPLICATION-KEY"))
+
+	req, keys, err := c.createRequest("POST", "/v1/series", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "provided_api_key", req.URL.Query().Get("api_key"))
+	assert.Equal(t, "redacted", keys.redactString("provided_api_key"))
+
+	c.SetKeys("new_api_key", "new_app_key")
+	req, _, err = c.createRequest("GET", "/v1/dashboard", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "new_api_key", req.Header.Get("DD-API-KEY"))
+
+	c.SetCredentialsProvider(&EnvCredentials{APIKeyVar: "TEST_DD_UNSET_API_KEY"})
+	_, _, err = c.createRequest("GET", "/v1/dashboard", nil)
+	assert.NotNil(t, err)
+}
+
+func TestSetKeysWhileRequesting(t *testing.T) {
+	s := makeTestServer(http.StatusOK, `{}`)
+	defer s.Close()
+	c := NewClient("sample_api_key", "sample_app_key")
+	c.SetBaseUrl(s.URL)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			assert.Nil(t, c.doJsonRequest("GET", "/v1/something", nil, nil))
+		}()
+		go func() {
+			defer wg.Done()
+			c.SetKeys("other_api_key", "other_app_key")
+		}()
+	}
+	wg.Wait()
+}
+
+// onceCredentials provides its credentials once, then either fails or
+// provides the next ones, as a rotating provider would.
+type onceCredentials struct {
+	m     sync.Mutex
+	first Credentials
+	next  *Credentials
+	used  bool
+}
+
+func (o *onceCredentials) Credentials() (Credentials, error) {
+	o.m.Lock()
+	defer o.m.Unlock()
+	if !o.used {
+		o.used = true
+		return o.first, nil
+	}
+	if o.next == nil {
+		return Credentials{}, fmt.Errorf("credentials unavailable")
+	}
+	return *o.next, nil
+}
+
+func TestRedactionUsesSentCredentials(t *testing.T) {
+	// A closed server makes requests fail with an error holding their URL.
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	s.Close()
+
+	sent := Credentials{APIKey: "SECRETAPIKEY", AppKey: "SECRETAPPKEY"}
+	for _, provider := range []*onceCredentials{
+		{first: sent},
+		{first: sent, next: &Credentials{APIKey: "rotated_api_key", AppKey: "rotated_app_key"}},
+	} {
+		logger := &testLogger{}
+		c := NewClientWithOptions(WithCredentialsProvider(provider), WithBaseUrl(s.URL),
+			WithLogger(logger), WithDebug(true), WithRetryPolicy(noRetryPolicy{}))
+
+		err := c.PostMetrics([]Metric{{Metric: String("foo.bar")}})
+		if assert.NotNil(t, err) {
+			assert.NotContains(t, err.Error(), "SECRET")
+			assert.Contains(t, err.Error(), "api_key=redacted")
+		}
+		for _, record := range logger.records {
+			assert.NotContains(t, fmt.Sprint(record.args), "SECRET")
+		}
+		assert.NotEmpty(t, logger.records)
+	}
+}
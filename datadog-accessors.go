@@ -4,7 +4,7 @@
  *
  * Please see the included LICENSE file for licensing information.
  *
- * Copyright 2020 by authors and contributors.
+ * Copyright 2026 by authors and contributors.
 */
 
 package datadog
@@ -5253,6 +5253,99 @@ func (d *DistributionRequest) SetStyle(v WidgetRequestStyle) {
 	d.Style = &v
 }
 
+// GetHost returns the Host field if non-nil, zero value otherwise.
+func (d *DistributionSeries) GetHost() string {
+	if d == nil || d.Host == nil {
+		return ""
+	}
+	return *d.Host
+}
+
+// GetHostOk returns a tuple with the Host field if it's non-nil, zero value otherwise
+// and a boolean to check if the value has been set.
+func (d *DistributionSeries) GetHostOk() (string, bool) {
+	if d == nil || d.Host == nil {
+		return "", false
+	}
+	return *d.Host, true
+}
+
+// HasHost returns a boolean if a field has been set.
+func (d *DistributionSeries) HasHost() bool {
+	if d != nil && d.Host != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetHost allocates a new d.Host and returns the pointer to it.
+func (d *DistributionSeries) SetHost(v string) {
+	d.Host = &v
+}
+
+// GetMetric returns the Metric field if non-nil, zero value otherwise.
+func (d *DistributionSeries) GetMetric() string {
+	if d == nil || d.Metric == nil {
+		return ""
+	}
+	return *d.Metric
+}
+
+// GetMetricOk returns a tuple with the Metric field if it's non-nil, zero value otherwise
+// and a boolean to check if the value has been set.
+func (d *DistributionSeries) GetMetricOk() (string, bool) {
+	if d == nil || d.Metric == nil {
+		return "", false
+	}
+	return *d.Metric, true
+}
+
+// HasMetric returns a boolean if a field has been set.
+func (d *DistributionSeries) HasMetric() bool {
+	if d != nil && d.Metric != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetMetric allocates a new d.Metric and returns the pointer to it.
+func (d *DistributionSeries) SetMetric(v string) {
+	d.Metric = &v
+}
+
+// GetType returns the Type field if non-nil, zero value otherwise.
+func (d *DistributionSeries) GetType() string {
+	if d == nil || d.Type == nil {
+		return ""
+	}
+	return *d.Type
+}
+
+// GetTypeOk returns a tuple with the Type field if it's non-nil, zero value otherwise
+// and a boolean to check if the value has been set.
+func (d *DistributionSeries) GetTypeOk() (string, bool) {
+	if d == nil || d.Type == nil {
+		return "", false
+	}
+	return *d.Type, true
+}
+
+// HasType returns a boolean if a field has been set.
+func (d *DistributionSeries) HasType() bool {
+	if d != nil && d.Type != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetType allocates a new d.Type and returns the pointer to it.
+func (d *DistributionSeries) SetType(v string) {
+	d.Type = &v
+}
+
 // GetActive returns the Active field if non-nil, zero value otherwise.
 func (d *Downtime) GetActive() bool {
 	if d == nil || d.Active == nil {
@@ -5687,6 +5780,99 @@ func (d *Downtime) SetUpdaterID(v int) {
 	d.UpdaterID = &v
 }
 
+// GetDayStarts returns the DayStarts field if non-nil, zero value otherwise.
+func (e *EvaluationWindow) GetDayStarts() string {
+	if e == nil || e.DayStarts == nil {
+		return ""
+	}
+	return *e.DayStarts
+}
+
+// GetDayStartsOk returns a tuple with the DayStarts field if it's non-nil, zero value otherwise
+// and a boolean to check if the value has been set.
+func (e *EvaluationWindow) GetDayStartsOk() (string, bool) {
+	if e == nil || e.DayStarts == nil {
+		return "", false
+	}
+	return *e.DayStarts, true
+}
+
+// HasDayStarts returns a boolean if a field has been set.
+func (e *EvaluationWindow) HasDayStarts() bool {
+	if e != nil && e.DayStarts != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetDayStarts allocates a new e.DayStarts and returns the pointer to it.
+func (e *EvaluationWindow) SetDayStarts(v string) {
+	e.DayStarts = &v
+}
+
+// GetHourStarts returns the HourStarts field if non-nil, zero value otherwise.
+func (e *EvaluationWindow) GetHourStarts() int {
+	if e == nil || e.HourStarts == nil {
+		return 0
+	}
+	return *e.HourStarts
+}
+
+// GetHourStartsOk returns a tuple with the HourStarts field if it's non-nil, zero value otherwise
+// and a boolean to check if the value has been set.
+func (e *EvaluationWindow) GetHourStartsOk() (int, bool) {
+	if e == nil || e.HourStarts == nil {
+		return 0, false
+	}
+	return *e.HourStarts, true
+}
+
+// HasHourStarts returns a boolean if a field has been set.
+func (e *EvaluationWindow) HasHourStarts() bool {
+	if e != nil && e.HourStarts != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetHourStarts allocates a new e.HourStarts and returns the pointer to it.
+func (e *EvaluationWindow) SetHourStarts(v int) {
+	e.HourStarts = &v
+}
+
+// GetMonthStarts returns the MonthStarts field if non-nil, zero value otherwise.
+func (e *EvaluationWindow) GetMonthStarts() int {
+	if e == nil || e.MonthStarts == nil {
+		return 0
+	}
+	return *e.MonthStarts
+}
+
+// GetMonthStartsOk returns a tuple with the MonthStarts field if it's non-nil, zero value otherwise
+// and a boolean to check if the value has been set.
+func (e *EvaluationWindow) GetMonthStartsOk() (int, bool) {
+	if e == nil || e.MonthStarts == nil {
+		return 0, false
+	}
+	return *e.MonthStarts, true
+}
+
+// HasMonthStarts returns a boolean if a field has been set.
+func (e *EvaluationWindow) HasMonthStarts() bool {
+	if e != nil && e.MonthStarts != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetMonthStarts allocates a new e.MonthStarts and returns the pointer to it.
+func (e *EvaluationWindow) SetMonthStarts(v int) {
+	e.MonthStarts = &v
+}
+
 // GetAggregation returns the Aggregation field if non-nil, zero value otherwise.
 func (e *Event) GetAggregation() string {
 	if e == nil || e.Aggregation == nil {
@@ -8819,7 +9005,7 @@ func (g *GroupData) SetName(v string) {
 }
 
 // GetStatus returns the Status field if non-nil, zero value otherwise.
-func (g *GroupData) GetStatus() string {
+func (g *GroupData) GetStatus() MonitorStatus {
 	if g == nil || g.Status == nil {
 		return ""
 	}
@@ -8828,7 +9014,7 @@ func (g *GroupData) GetStatus() string {
 
 // GetStatusOk returns a tuple with the Status field if it's non-nil, zero value otherwise
 // and a boolean to check if the value has been set.
-func (g *GroupData) GetStatusOk() (string, bool) {
+func (g *GroupData) GetStatusOk() (MonitorStatus, bool) {
 	if g == nil || g.Status == nil {
 		return "", false
 	}
@@ -8845,7 +9031,7 @@ func (g *GroupData) HasStatus() bool {
 }
 
 // SetStatus allocates a new g.Status and returns the pointer to it.
-func (g *GroupData) SetStatus(v string) {
+func (g *GroupData) SetStatus(v MonitorStatus) {
 	g.Status = &v
 }
 
@@ -14120,7 +14306,7 @@ func (m *Monitor) SetOptions(v Options) {
 }
 
 // GetOverallState returns the OverallState field if non-nil, zero value otherwise.
-func (m *Monitor) GetOverallState() string {
+func (m *Monitor) GetOverallState() MonitorStatus {
 	if m == nil || m.OverallState == nil {
 		return ""
 	}
@@ -14129,7 +14315,7 @@ func (m *Monitor) GetOverallState() string {
 
 // GetOverallStateOk returns a tuple with the OverallState field if it's non-nil, zero value otherwise
 // and a boolean to check if the value has been set.
-func (m *Monitor) GetOverallStateOk() (string, bool) {
+func (m *Monitor) GetOverallStateOk() (MonitorStatus, bool) {
 	if m == nil || m.OverallState == nil {
 		return "", false
 	}
@@ -14146,7 +14332,7 @@ func (m *Monitor) HasOverallState() bool {
 }
 
 // SetOverallState allocates a new m.OverallState and returns the pointer to it.
-func (m *Monitor) SetOverallState(v string) {
+func (m *Monitor) SetOverallState(v MonitorStatus) {
 	m.OverallState = &v
 }
 
@@ -14181,6 +14367,37 @@ func (m *Monitor) SetOverallStateModified(v string) {
 	m.OverallStateModified = &v
 }
 
+// GetPriority returns the Priority field if non-nil, zero value otherwise.
+func (m *Monitor) GetPriority() int {
+	if m == nil || m.Priority == nil {
+		return 0
+	}
+	return *m.Priority
+}
+
+// GetPriorityOk returns a tuple with the Priority field if it's non-nil, zero value otherwise
+// and a boolean to check if the value has been set.
+func (m *Monitor) GetPriorityOk() (int, bool) {
+	if m == nil || m.Priority == nil {
+		return 0, false
+	}
+	return *m.Priority, true
+}
+
+// HasPriority returns a boolean if a field has been set.
+func (m *Monitor) HasPriority() bool {
+	if m != nil && m.Priority != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetPriority allocates a new m.Priority and returns the pointer to it.
+func (m *Monitor) SetPriority(v int) {
+	m.Priority = &v
+}
+
 // GetQuery returns the Query field if non-nil, zero value otherwise.
 func (m *Monitor) GetQuery() string {
 	if m == nil || m.Query == nil {
@@ -14243,6 +14460,37 @@ func (m *Monitor) SetType(v string) {
 	m.Type = &v
 }
 
+// GetDependents returns the Dependents field if non-nil, zero value otherwise.
+func (m *MonitorInUseError) GetDependents() MonitorDependents {
+	if m == nil || m.Dependents == nil {
+		return MonitorDependents{}
+	}
+	return *m.Dependents
+}
+
+// GetDependentsOk returns a tuple with the Dependents field if it's non-nil, zero value otherwise
+// and a boolean to check if the value has been set.
+func (m *MonitorInUseError) GetDependentsOk() (MonitorDependents, bool) {
+	if m == nil || m.Dependents == nil {
+		return MonitorDependents{}, false
+	}
+	return *m.Dependents, true
+}
+
+// HasDependents returns a boolean if a field has been set.
+func (m *MonitorInUseError) HasDependents() bool {
+	if m != nil && m.Dependents != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetDependents allocates a new m.Dependents and returns the pointer to it.
+func (m *MonitorInUseError) SetDependents(v MonitorDependents) {
+	m.Dependents = &v
+}
+
 // GetRenotifyInterval returns the RenotifyInterval field if non-nil, zero value otherwise.
 func (m *MonitorOptions) GetRenotifyInterval() int {
 	if m == nil || m.RenotifyInterval == nil {
@@ -14305,6 +14553,68 @@ func (m *MonitorQueryOpts) SetName(v string) {
 	m.Name = &v
 }
 
+// GetPage returns the Page field if non-nil, zero value otherwise.
+func (m *MonitorQueryOpts) GetPage() int {
+	if m == nil || m.Page == nil {
+		return 0
+	}
+	return *m.Page
+}
+
+// GetPageOk returns a tuple with the Page field if it's non-nil, zero value otherwise
+// and a boolean to check if the value has been set.
+func (m *MonitorQueryOpts) GetPageOk() (int, bool) {
+	if m == nil || m.Page == nil {
+		return 0, false
+	}
+	return *m.Page, true
+}
+
+// HasPage returns a boolean if a field has been set.
+func (m *MonitorQueryOpts) HasPage() bool {
+	if m != nil && m.Page != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetPage allocates a new m.Page and returns the pointer to it.
+func (m *MonitorQueryOpts) SetPage(v int) {
+	m.Page = &v
+}
+
+// GetPageSize returns the PageSize field if non-nil, zero value otherwise.
+func (m *MonitorQueryOpts) GetPageSize() int {
+	if m == nil || m.PageSize == nil {
+		return 0
+	}
+	return *m.PageSize
+}
+
+// GetPageSizeOk returns a tuple with the PageSize field if it's non-nil, zero value otherwise
+// and a boolean to check if the value has been set.
+func (m *MonitorQueryOpts) GetPageSizeOk() (int, bool) {
+	if m == nil || m.PageSize == nil {
+		return 0, false
+	}
+	return *m.PageSize, true
+}
+
+// HasPageSize returns a boolean if a field has been set.
+func (m *MonitorQueryOpts) HasPageSize() bool {
+	if m != nil && m.PageSize != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetPageSize allocates a new m.PageSize and returns the pointer to it.
+func (m *MonitorQueryOpts) SetPageSize(v int) {
+	m.PageSize = &v
+}
+
 // GetWithDowntimes returns the WithDowntimes field if non-nil, zero value otherwise.
 func (m *MonitorQueryOpts) GetWithDowntimes() bool {
 	if m == nil || m.WithDowntimes == nil {
@@ -14336,102 +14646,784 @@ func (m *MonitorQueryOpts) SetWithDowntimes(v bool) {
 	m.WithDowntimes = &v
 }
 
-// GetEnd returns the End field if non-nil, zero value otherwise.
-func (m *MuteMonitorScope) GetEnd() int {
-	if m == nil || m.End == nil {
+// GetCount returns the Count field if non-nil, zero value otherwise.
+func (m *MonitorSearchCount) GetCount() int {
+	if m == nil || m.Count == nil {
 		return 0
 	}
-	return *m.End
+	return *m.Count
 }
 
-// GetEndOk returns a tuple with the End field if it's non-nil, zero value otherwise
+// GetCountOk returns a tuple with the Count field if it's non-nil, zero value otherwise
 // and a boolean to check if the value has been set.
-func (m *MuteMonitorScope) GetEndOk() (int, bool) {
-	if m == nil || m.End == nil {
+func (m *MonitorSearchCount) GetCountOk() (int, bool) {
+	if m == nil || m.Count == nil {
 		return 0, false
 	}
-	return *m.End, true
+	return *m.Count, true
 }
 
-// HasEnd returns a boolean if a field has been set.
-func (m *MuteMonitorScope) HasEnd() bool {
-	if m != nil && m.End != nil {
+// HasCount returns a boolean if a field has been set.
+func (m *MonitorSearchCount) HasCount() bool {
+	if m != nil && m.Count != nil {
 		return true
 	}
 
 	return false
 }
 
-// SetEnd allocates a new m.End and returns the pointer to it.
-func (m *MuteMonitorScope) SetEnd(v int) {
-	m.End = &v
+// SetCount allocates a new m.Count and returns the pointer to it.
+func (m *MonitorSearchCount) SetCount(v int) {
+	m.Count = &v
 }
 
-// GetScope returns the Scope field if non-nil, zero value otherwise.
-func (m *MuteMonitorScope) GetScope() string {
-	if m == nil || m.Scope == nil {
+// GetName returns the Name field if non-nil, zero value otherwise.
+func (m *MonitorSearchCount) GetName() string {
+	if m == nil || m.Name == nil {
 		return ""
 	}
-	return *m.Scope
+	return *m.Name
 }
 
-// GetScopeOk returns a tuple with the Scope field if it's non-nil, zero value otherwise
+// GetNameOk returns a tuple with the Name field if it's non-nil, zero value otherwise
 // and a boolean to check if the value has been set.
-func (m *MuteMonitorScope) GetScopeOk() (string, bool) {
-	if m == nil || m.Scope == nil {
+func (m *MonitorSearchCount) GetNameOk() (string, bool) {
+	if m == nil || m.Name == nil {
 		return "", false
 	}
-	return *m.Scope, true
+	return *m.Name, true
 }
 
-// HasScope returns a boolean if a field has been set.
-func (m *MuteMonitorScope) HasScope() bool {
-	if m != nil && m.Scope != nil {
+// HasName returns a boolean if a field has been set.
+func (m *MonitorSearchCount) HasName() bool {
+	if m != nil && m.Name != nil {
 		return true
 	}
 
 	return false
 }
 
-// SetScope allocates a new m.Scope and returns the pointer to it.
-func (m *MuteMonitorScope) SetScope(v string) {
-	m.Scope = &v
+// SetName allocates a new m.Name and returns the pointer to it.
+func (m *MonitorSearchCount) SetName(v string) {
+	m.Name = &v
 }
 
-// GetFilter returns the Filter field if non-nil, zero value otherwise.
-func (n *NestedPipeline) GetFilter() FilterConfiguration {
-	if n == nil || n.Filter == nil {
-		return FilterConfiguration{}
+// GetPage returns the Page field if non-nil, zero value otherwise.
+func (m *MonitorSearchMetadata) GetPage() int {
+	if m == nil || m.Page == nil {
+		return 0
 	}
-	return *n.Filter
+	return *m.Page
 }
 
-// GetFilterOk returns a tuple with the Filter field if it's non-nil, zero value otherwise
+// GetPageOk returns a tuple with the Page field if it's non-nil, zero value otherwise
 // and a boolean to check if the value has been set.
-func (n *NestedPipeline) GetFilterOk() (FilterConfiguration, bool) {
-	if n == nil || n.Filter == nil {
-		return FilterConfiguration{}, false
+func (m *MonitorSearchMetadata) GetPageOk() (int, bool) {
+	if m == nil || m.Page == nil {
+		return 0, false
 	}
-	return *n.Filter, true
+	return *m.Page, true
 }
 
-// HasFilter returns a boolean if a field has been set.
-func (n *NestedPipeline) HasFilter() bool {
-	if n != nil && n.Filter != nil {
+// HasPage returns a boolean if a field has been set.
+func (m *MonitorSearchMetadata) HasPage() bool {
+	if m != nil && m.Page != nil {
 		return true
 	}
 
 	return false
 }
 
-// SetFilter allocates a new n.Filter and returns the pointer to it.
-func (n *NestedPipeline) SetFilter(v FilterConfiguration) {
-	n.Filter = &v
+// SetPage allocates a new m.Page and returns the pointer to it.
+func (m *MonitorSearchMetadata) SetPage(v int) {
+	m.Page = &v
 }
 
-// GetBackgroundColor returns the BackgroundColor field if non-nil, zero value otherwise.
-func (n *NoteDefinition) GetBackgroundColor() string {
-	if n == nil || n.BackgroundColor == nil {
+// GetPageCount returns the PageCount field if non-nil, zero value otherwise.
+func (m *MonitorSearchMetadata) GetPageCount() int {
+	if m == nil || m.PageCount == nil {
+		return 0
+	}
+	return *m.PageCount
+}
+
+// GetPageCountOk returns a tuple with the PageCount field if it's non-nil, zero value otherwise
+// and a boolean to check if the value has been set.
+func (m *MonitorSearchMetadata) GetPageCountOk() (int, bool) {
+	if m == nil || m.PageCount == nil {
+		return 0, false
+	}
+	return *m.PageCount, true
+}
+
+// HasPageCount returns a boolean if a field has been set.
+func (m *MonitorSearchMetadata) HasPageCount() bool {
+	if m != nil && m.PageCount != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetPageCount allocates a new m.PageCount and returns the pointer to it.
+func (m *MonitorSearchMetadata) SetPageCount(v int) {
+	m.PageCount = &v
+}
+
+// GetPerPage returns the PerPage field if non-nil, zero value otherwise.
+func (m *MonitorSearchMetadata) GetPerPage() int {
+	if m == nil || m.PerPage == nil {
+		return 0
+	}
+	return *m.PerPage
+}
+
+// GetPerPageOk returns a tuple with the PerPage field if it's non-nil, zero value otherwise
+// and a boolean to check if the value has been set.
+func (m *MonitorSearchMetadata) GetPerPageOk() (int, bool) {
+	if m == nil || m.PerPage == nil {
+		return 0, false
+	}
+	return *m.PerPage, true
+}
+
+// HasPerPage returns a boolean if a field has been set.
+func (m *MonitorSearchMetadata) HasPerPage() bool {
+	if m != nil && m.PerPage != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetPerPage allocates a new m.PerPage and returns the pointer to it.
+func (m *MonitorSearchMetadata) SetPerPage(v int) {
+	m.PerPage = &v
+}
+
+// GetTotalCount returns the TotalCount field if non-nil, zero value otherwise.
+func (m *MonitorSearchMetadata) GetTotalCount() int {
+	if m == nil || m.TotalCount == nil {
+		return 0
+	}
+	return *m.TotalCount
+}
+
+// GetTotalCountOk returns a tuple with the TotalCount field if it's non-nil, zero value otherwise
+// and a boolean to check if the value has been set.
+func (m *MonitorSearchMetadata) GetTotalCountOk() (int, bool) {
+	if m == nil || m.TotalCount == nil {
+		return 0, false
+	}
+	return *m.TotalCount, true
+}
+
+// HasTotalCount returns a boolean if a field has been set.
+func (m *MonitorSearchMetadata) HasTotalCount() bool {
+	if m != nil && m.TotalCount != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetTotalCount allocates a new m.TotalCount and returns the pointer to it.
+func (m *MonitorSearchMetadata) SetTotalCount(v int) {
+	m.TotalCount = &v
+}
+
+// GetCount returns the Count field if non-nil, zero value otherwise.
+func (m *MonitorSearchMutedCount) GetCount() int {
+	if m == nil || m.Count == nil {
+		return 0
+	}
+	return *m.Count
+}
+
+// GetCountOk returns a tuple with the Count field if it's non-nil, zero value otherwise
+// and a boolean to check if the value has been set.
+func (m *MonitorSearchMutedCount) GetCountOk() (int, bool) {
+	if m == nil || m.Count == nil {
+		return 0, false
+	}
+	return *m.Count, true
+}
+
+// HasCount returns a boolean if a field has been set.
+func (m *MonitorSearchMutedCount) HasCount() bool {
+	if m != nil && m.Count != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetCount allocates a new m.Count and returns the pointer to it.
+func (m *MonitorSearchMutedCount) SetCount(v int) {
+	m.Count = &v
+}
+
+// GetName returns the Name field if non-nil, zero value otherwise.
+func (m *MonitorSearchMutedCount) GetName() bool {
+	if m == nil || m.Name == nil {
+		return false
+	}
+	return *m.Name
+}
+
+// GetNameOk returns a tuple with the Name field if it's non-nil, zero value otherwise
+// and a boolean to check if the value has been set.
+func (m *MonitorSearchMutedCount) GetNameOk() (bool, bool) {
+	if m == nil || m.Name == nil {
+		return false, false
+	}
+	return *m.Name, true
+}
+
+// HasName returns a boolean if a field has been set.
+func (m *MonitorSearchMutedCount) HasName() bool {
+	if m != nil && m.Name != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetName allocates a new m.Name and returns the pointer to it.
+func (m *MonitorSearchMutedCount) SetName(v bool) {
+	m.Name = &v
+}
+
+// GetHandle returns the Handle field if non-nil, zero value otherwise.
+func (m *MonitorSearchNotification) GetHandle() string {
+	if m == nil || m.Handle == nil {
+		return ""
+	}
+	return *m.Handle
+}
+
+// GetHandleOk returns a tuple with the Handle field if it's non-nil, zero value otherwise
+// and a boolean to check if the value has been set.
+func (m *MonitorSearchNotification) GetHandleOk() (string, bool) {
+	if m == nil || m.Handle == nil {
+		return "", false
+	}
+	return *m.Handle, true
+}
+
+// HasHandle returns a boolean if a field has been set.
+func (m *MonitorSearchNotification) HasHandle() bool {
+	if m != nil && m.Handle != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetHandle allocates a new m.Handle and returns the pointer to it.
+func (m *MonitorSearchNotification) SetHandle(v string) {
+	m.Handle = &v
+}
+
+// GetName returns the Name field if non-nil, zero value otherwise.
+func (m *MonitorSearchNotification) GetName() string {
+	if m == nil || m.Name == nil {
+		return ""
+	}
+	return *m.Name
+}
+
+// GetNameOk returns a tuple with the Name field if it's non-nil, zero value otherwise
+// and a boolean to check if the value has been set.
+func (m *MonitorSearchNotification) GetNameOk() (string, bool) {
+	if m == nil || m.Name == nil {
+		return "", false
+	}
+	return *m.Name, true
+}
+
+// HasName returns a boolean if a field has been set.
+func (m *MonitorSearchNotification) HasName() bool {
+	if m != nil && m.Name != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetName allocates a new m.Name and returns the pointer to it.
+func (m *MonitorSearchNotification) SetName(v string) {
+	m.Name = &v
+}
+
+// GetQuery returns the Query field if non-nil, zero value otherwise.
+func (m *MonitorSearchOpts) GetQuery() string {
+	if m == nil || m.Query == nil {
+		return ""
+	}
+	return *m.Query
+}
+
+// GetQueryOk returns a tuple with the Query field if it's non-nil, zero value otherwise
+// and a boolean to check if the value has been set.
+func (m *MonitorSearchOpts) GetQueryOk() (string, bool) {
+	if m == nil || m.Query == nil {
+		return "", false
+	}
+	return *m.Query, true
+}
+
+// HasQuery returns a boolean if a field has been set.
+func (m *MonitorSearchOpts) HasQuery() bool {
+	if m != nil && m.Query != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetQuery allocates a new m.Query and returns the pointer to it.
+func (m *MonitorSearchOpts) SetQuery(v string) {
+	m.Query = &v
+}
+
+// GetSort returns the Sort field if non-nil, zero value otherwise.
+func (m *MonitorSearchOpts) GetSort() string {
+	if m == nil || m.Sort == nil {
+		return ""
+	}
+	return *m.Sort
+}
+
+// GetSortOk returns a tuple with the Sort field if it's non-nil, zero value otherwise
+// and a boolean to check if the value has been set.
+func (m *MonitorSearchOpts) GetSortOk() (string, bool) {
+	if m == nil || m.Sort == nil {
+		return "", false
+	}
+	return *m.Sort, true
+}
+
+// HasSort returns a boolean if a field has been set.
+func (m *MonitorSearchOpts) HasSort() bool {
+	if m != nil && m.Sort != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetSort allocates a new m.Sort and returns the pointer to it.
+func (m *MonitorSearchOpts) SetSort(v string) {
+	m.Sort = &v
+}
+
+// GetCounts returns the Counts field if non-nil, zero value otherwise.
+func (m *MonitorSearchResult) GetCounts() MonitorSearchCounts {
+	if m == nil || m.Counts == nil {
+		return MonitorSearchCounts{}
+	}
+	return *m.Counts
+}
+
+// GetCountsOk returns a tuple with the Counts field if it's non-nil, zero value otherwise
+// and a boolean to check if the value has been set.
+func (m *MonitorSearchResult) GetCountsOk() (MonitorSearchCounts, bool) {
+	if m == nil || m.Counts == nil {
+		return MonitorSearchCounts{}, false
+	}
+	return *m.Counts, true
+}
+
+// HasCounts returns a boolean if a field has been set.
+func (m *MonitorSearchResult) HasCounts() bool {
+	if m != nil && m.Counts != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetCounts allocates a new m.Counts and returns the pointer to it.
+func (m *MonitorSearchResult) SetCounts(v MonitorSearchCounts) {
+	m.Counts = &v
+}
+
+// GetMetadata returns the Metadata field if non-nil, zero value otherwise.
+func (m *MonitorSearchResult) GetMetadata() MonitorSearchMetadata {
+	if m == nil || m.Metadata == nil {
+		return MonitorSearchMetadata{}
+	}
+	return *m.Metadata
+}
+
+// GetMetadataOk returns a tuple with the Metadata field if it's non-nil, zero value otherwise
+// and a boolean to check if the value has been set.
+func (m *MonitorSearchResult) GetMetadataOk() (MonitorSearchMetadata, bool) {
+	if m == nil || m.Metadata == nil {
+		return MonitorSearchMetadata{}, false
+	}
+	return *m.Metadata, true
+}
+
+// HasMetadata returns a boolean if a field has been set.
+func (m *MonitorSearchResult) HasMetadata() bool {
+	if m != nil && m.Metadata != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetMetadata allocates a new m.Metadata and returns the pointer to it.
+func (m *MonitorSearchResult) SetMetadata(v MonitorSearchMetadata) {
+	m.Metadata = &v
+}
+
+// GetClassification returns the Classification field if non-nil, zero value otherwise.
+func (m *MonitorSearchResultMonitor) GetClassification() string {
+	if m == nil || m.Classification == nil {
+		return ""
+	}
+	return *m.Classification
+}
+
+// GetClassificationOk returns a tuple with the Classification field if it's non-nil, zero value otherwise
+// and a boolean to check if the value has been set.
+func (m *MonitorSearchResultMonitor) GetClassificationOk() (string, bool) {
+	if m == nil || m.Classification == nil {
+		return "", false
+	}
+	return *m.Classification, true
+}
+
+// HasClassification returns a boolean if a field has been set.
+func (m *MonitorSearchResultMonitor) HasClassification() bool {
+	if m != nil && m.Classification != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetClassification allocates a new m.Classification and returns the pointer to it.
+func (m *MonitorSearchResultMonitor) SetClassification(v string) {
+	m.Classification = &v
+}
+
+// GetCreator returns the Creator field if non-nil, zero value otherwise.
+func (m *MonitorSearchResultMonitor) GetCreator() Creator {
+	if m == nil || m.Creator == nil {
+		return Creator{}
+	}
+	return *m.Creator
+}
+
+// GetCreatorOk returns a tuple with the Creator field if it's non-nil, zero value otherwise
+// and a boolean to check if the value has been set.
+func (m *MonitorSearchResultMonitor) GetCreatorOk() (Creator, bool) {
+	if m == nil || m.Creator == nil {
+		return Creator{}, false
+	}
+	return *m.Creator, true
+}
+
+// HasCreator returns a boolean if a field has been set.
+func (m *MonitorSearchResultMonitor) HasCreator() bool {
+	if m != nil && m.Creator != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetCreator allocates a new m.Creator and returns the pointer to it.
+func (m *MonitorSearchResultMonitor) SetCreator(v Creator) {
+	m.Creator = &v
+}
+
+// GetId returns the Id field if non-nil, zero value otherwise.
+func (m *MonitorSearchResultMonitor) GetId() int {
+	if m == nil || m.Id == nil {
+		return 0
+	}
+	return *m.Id
+}
+
+// GetIdOk returns a tuple with the Id field if it's non-nil, zero value otherwise
+// and a boolean to check if the value has been set.
+func (m *MonitorSearchResultMonitor) GetIdOk() (int, bool) {
+	if m == nil || m.Id == nil {
+		return 0, false
+	}
+	return *m.Id, true
+}
+
+// HasId returns a boolean if a field has been set.
+func (m *MonitorSearchResultMonitor) HasId() bool {
+	if m != nil && m.Id != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetId allocates a new m.Id and returns the pointer to it.
+func (m *MonitorSearchResultMonitor) SetId(v int) {
+	m.Id = &v
+}
+
+// GetLastTriggeredTs returns the LastTriggeredTs field if non-nil, zero value otherwise.
+func (m *MonitorSearchResultMonitor) GetLastTriggeredTs() int {
+	if m == nil || m.LastTriggeredTs == nil {
+		return 0
+	}
+	return *m.LastTriggeredTs
+}
+
+// GetLastTriggeredTsOk returns a tuple with the LastTriggeredTs field if it's non-nil, zero value otherwise
+// and a boolean to check if the value has been set.
+func (m *MonitorSearchResultMonitor) GetLastTriggeredTsOk() (int, bool) {
+	if m == nil || m.LastTriggeredTs == nil {
+		return 0, false
+	}
+	return *m.LastTriggeredTs, true
+}
+
+// HasLastTriggeredTs returns a boolean if a field has been set.
+func (m *MonitorSearchResultMonitor) HasLastTriggeredTs() bool {
+	if m != nil && m.LastTriggeredTs != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetLastTriggeredTs allocates a new m.LastTriggeredTs and returns the pointer to it.
+func (m *MonitorSearchResultMonitor) SetLastTriggeredTs(v int) {
+	m.LastTriggeredTs = &v
+}
+
+// GetName returns the Name field if non-nil, zero value otherwise.
+func (m *MonitorSearchResultMonitor) GetName() string {
+	if m == nil || m.Name == nil {
+		return ""
+	}
+	return *m.Name
+}
+
+// GetNameOk returns a tuple with the Name field if it's non-nil, zero value otherwise
+// and a boolean to check if the value has been set.
+func (m *MonitorSearchResultMonitor) GetNameOk() (string, bool) {
+	if m == nil || m.Name == nil {
+		return "", false
+	}
+	return *m.Name, true
+}
+
+// HasName returns a boolean if a field has been set.
+func (m *MonitorSearchResultMonitor) HasName() bool {
+	if m != nil && m.Name != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetName allocates a new m.Name and returns the pointer to it.
+func (m *MonitorSearchResultMonitor) SetName(v string) {
+	m.Name = &v
+}
+
+// GetQuery returns the Query field if non-nil, zero value otherwise.
+func (m *MonitorSearchResultMonitor) GetQuery() string {
+	if m == nil || m.Query == nil {
+		return ""
+	}
+	return *m.Query
+}
+
+// GetQueryOk returns a tuple with the Query field if it's non-nil, zero value otherwise
+// and a boolean to check if the value has been set.
+func (m *MonitorSearchResultMonitor) GetQueryOk() (string, bool) {
+	if m == nil || m.Query == nil {
+		return "", false
+	}
+	return *m.Query, true
+}
+
+// HasQuery returns a boolean if a field has been set.
+func (m *MonitorSearchResultMonitor) HasQuery() bool {
+	if m != nil && m.Query != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetQuery allocates a new m.Query and returns the pointer to it.
+func (m *MonitorSearchResultMonitor) SetQuery(v string) {
+	m.Query = &v
+}
+
+// GetStatus returns the Status field if non-nil, zero value otherwise.
+func (m *MonitorSearchResultMonitor) GetStatus() string {
+	if m == nil || m.Status == nil {
+		return ""
+	}
+	return *m.Status
+}
+
+// GetStatusOk returns a tuple with the Status field if it's non-nil, zero value otherwise
+// and a boolean to check if the value has been set.
+func (m *MonitorSearchResultMonitor) GetStatusOk() (string, bool) {
+	if m == nil || m.Status == nil {
+		return "", false
+	}
+	return *m.Status, true
+}
+
+// HasStatus returns a boolean if a field has been set.
+func (m *MonitorSearchResultMonitor) HasStatus() bool {
+	if m != nil && m.Status != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetStatus allocates a new m.Status and returns the pointer to it.
+func (m *MonitorSearchResultMonitor) SetStatus(v string) {
+	m.Status = &v
+}
+
+// GetType returns the Type field if non-nil, zero value otherwise.
+func (m *MonitorSearchResultMonitor) GetType() string {
+	if m == nil || m.Type == nil {
+		return ""
+	}
+	return *m.Type
+}
+
+// GetTypeOk returns a tuple with the Type field if it's non-nil, zero value otherwise
+// and a boolean to check if the value has been set.
+func (m *MonitorSearchResultMonitor) GetTypeOk() (string, bool) {
+	if m == nil || m.Type == nil {
+		return "", false
+	}
+	return *m.Type, true
+}
+
+// HasType returns a boolean if a field has been set.
+func (m *MonitorSearchResultMonitor) HasType() bool {
+	if m != nil && m.Type != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetType allocates a new m.Type and returns the pointer to it.
+func (m *MonitorSearchResultMonitor) SetType(v string) {
+	m.Type = &v
+}
+
+// GetEnd returns the End field if non-nil, zero value otherwise.
+func (m *MuteMonitorScope) GetEnd() int {
+	if m == nil || m.End == nil {
+		return 0
+	}
+	return *m.End
+}
+
+// GetEndOk returns a tuple with the End field if it's non-nil, zero value otherwise
+// and a boolean to check if the value has been set.
+func (m *MuteMonitorScope) GetEndOk() (int, bool) {
+	if m == nil || m.End == nil {
+		return 0, false
+	}
+	return *m.End, true
+}
+
+// HasEnd returns a boolean if a field has been set.
+func (m *MuteMonitorScope) HasEnd() bool {
+	if m != nil && m.End != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetEnd allocates a new m.End and returns the pointer to it.
+func (m *MuteMonitorScope) SetEnd(v int) {
+	m.End = &v
+}
+
+// GetScope returns the Scope field if non-nil, zero value otherwise.
+func (m *MuteMonitorScope) GetScope() string {
+	if m == nil || m.Scope == nil {
+		return ""
+	}
+	return *m.Scope
+}
+
+// GetScopeOk returns a tuple with the Scope field if it's non-nil, zero value otherwise
+// and a boolean to check if the value has been set.
+func (m *MuteMonitorScope) GetScopeOk() (string, bool) {
+	if m == nil || m.Scope == nil {
+		return "", false
+	}
+	return *m.Scope, true
+}
+
+// HasScope returns a boolean if a field has been set.
+func (m *MuteMonitorScope) HasScope() bool {
+	if m != nil && m.Scope != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetScope allocates a new m.Scope and returns the pointer to it.
+func (m *MuteMonitorScope) SetScope(v string) {
+	m.Scope = &v
+}
+
+// GetFilter returns the Filter field if non-nil, zero value otherwise.
+func (n *NestedPipeline) GetFilter() FilterConfiguration {
+	if n == nil || n.Filter == nil {
+		return FilterConfiguration{}
+	}
+	return *n.Filter
+}
+
+// GetFilterOk returns a tuple with the Filter field if it's non-nil, zero value otherwise
+// and a boolean to check if the value has been set.
+func (n *NestedPipeline) GetFilterOk() (FilterConfiguration, bool) {
+	if n == nil || n.Filter == nil {
+		return FilterConfiguration{}, false
+	}
+	return *n.Filter, true
+}
+
+// HasFilter returns a boolean if a field has been set.
+func (n *NestedPipeline) HasFilter() bool {
+	if n != nil && n.Filter != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetFilter allocates a new n.Filter and returns the pointer to it.
+func (n *NestedPipeline) SetFilter(v FilterConfiguration) {
+	n.Filter = &v
+}
+
+// GetBackgroundColor returns the BackgroundColor field if non-nil, zero value otherwise.
+func (n *NoteDefinition) GetBackgroundColor() string {
+	if n == nil || n.BackgroundColor == nil {
 		return ""
 	}
 	return *n.BackgroundColor
@@ -14677,6 +15669,37 @@ func (n *NoteDefinition) SetType(v string) {
 	n.Type = &v
 }
 
+// GetSlack returns the Slack field if non-nil, zero value otherwise.
+func (n *NotificationIntegrations) GetSlack() IntegrationSlackRequest {
+	if n == nil || n.Slack == nil {
+		return IntegrationSlackRequest{}
+	}
+	return *n.Slack
+}
+
+// GetSlackOk returns a tuple with the Slack field if it's non-nil, zero value otherwise
+// and a boolean to check if the value has been set.
+func (n *NotificationIntegrations) GetSlackOk() (IntegrationSlackRequest, bool) {
+	if n == nil || n.Slack == nil {
+		return IntegrationSlackRequest{}, false
+	}
+	return *n.Slack, true
+}
+
+// HasSlack returns a boolean if a field has been set.
+func (n *NotificationIntegrations) HasSlack() bool {
+	if n != nil && n.Slack != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetSlack allocates a new n.Slack and returns the pointer to it.
+func (n *NotificationIntegrations) SetSlack(v IntegrationSlackRequest) {
+	n.Slack = &v
+}
+
 // GetEnableLogsSample returns the EnableLogsSample field if non-nil, zero value otherwise.
 func (o *Options) GetEnableLogsSample() bool {
 	if o == nil || o.EnableLogsSample == nil {
@@ -14770,6 +15793,37 @@ func (o *Options) SetEvaluationDelay(v int) {
 	o.EvaluationDelay = &v
 }
 
+// GetGroupbySimpleMonitor returns the GroupbySimpleMonitor field if non-nil, zero value otherwise.
+func (o *Options) GetGroupbySimpleMonitor() bool {
+	if o == nil || o.GroupbySimpleMonitor == nil {
+		return false
+	}
+	return *o.GroupbySimpleMonitor
+}
+
+// GetGroupbySimpleMonitorOk returns a tuple with the GroupbySimpleMonitor field if it's non-nil, zero value otherwise
+// and a boolean to check if the value has been set.
+func (o *Options) GetGroupbySimpleMonitorOk() (bool, bool) {
+	if o == nil || o.GroupbySimpleMonitor == nil {
+		return false, false
+	}
+	return *o.GroupbySimpleMonitor, true
+}
+
+// HasGroupbySimpleMonitor returns a boolean if a field has been set.
+func (o *Options) HasGroupbySimpleMonitor() bool {
+	if o != nil && o.GroupbySimpleMonitor != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetGroupbySimpleMonitor allocates a new o.GroupbySimpleMonitor and returns the pointer to it.
+func (o *Options) SetGroupbySimpleMonitor(v bool) {
+	o.GroupbySimpleMonitor = &v
+}
+
 // GetIncludeTags returns the IncludeTags field if non-nil, zero value otherwise.
 func (o *Options) GetIncludeTags() bool {
 	if o == nil || o.IncludeTags == nil {
@@ -14832,6 +15886,37 @@ func (o *Options) SetLocked(v bool) {
 	o.Locked = &v
 }
 
+// GetNewGroupDelay returns the NewGroupDelay field if non-nil, zero value otherwise.
+func (o *Options) GetNewGroupDelay() int {
+	if o == nil || o.NewGroupDelay == nil {
+		return 0
+	}
+	return *o.NewGroupDelay
+}
+
+// GetNewGroupDelayOk returns a tuple with the NewGroupDelay field if it's non-nil, zero value otherwise
+// and a boolean to check if the value has been set.
+func (o *Options) GetNewGroupDelayOk() (int, bool) {
+	if o == nil || o.NewGroupDelay == nil {
+		return 0, false
+	}
+	return *o.NewGroupDelay, true
+}
+
+// HasNewGroupDelay returns a boolean if a field has been set.
+func (o *Options) HasNewGroupDelay() bool {
+	if o != nil && o.NewGroupDelay != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetNewGroupDelay allocates a new o.NewGroupDelay and returns the pointer to it.
+func (o *Options) SetNewGroupDelay(v int) {
+	o.NewGroupDelay = &v
+}
+
 // GetNewHostDelay returns the NewHostDelay field if non-nil, zero value otherwise.
 func (o *Options) GetNewHostDelay() int {
 	if o == nil || o.NewHostDelay == nil {
@@ -14925,6 +16010,37 @@ func (o *Options) SetNotifyNoData(v bool) {
 	o.NotifyNoData = &v
 }
 
+// GetOnMissingData returns the OnMissingData field if non-nil, zero value otherwise.
+func (o *Options) GetOnMissingData() string {
+	if o == nil || o.OnMissingData == nil {
+		return ""
+	}
+	return *o.OnMissingData
+}
+
+// GetOnMissingDataOk returns a tuple with the OnMissingData field if it's non-nil, zero value otherwise
+// and a boolean to check if the value has been set.
+func (o *Options) GetOnMissingDataOk() (string, bool) {
+	if o == nil || o.OnMissingData == nil {
+		return "", false
+	}
+	return *o.OnMissingData, true
+}
+
+// HasOnMissingData returns a boolean if a field has been set.
+func (o *Options) HasOnMissingData() bool {
+	if o != nil && o.OnMissingData != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetOnMissingData allocates a new o.OnMissingData and returns the pointer to it.
+func (o *Options) SetOnMissingData(v string) {
+	o.OnMissingData = &v
+}
+
 // GetQueryConfig returns the QueryConfig field if non-nil, zero value otherwise.
 func (o *Options) GetQueryConfig() QueryConfig {
 	if o == nil || o.QueryConfig == nil {
@@ -14987,6 +16103,37 @@ func (o *Options) SetRenotifyInterval(v int) {
 	o.RenotifyInterval = &v
 }
 
+// GetRenotifyOccurrences returns the RenotifyOccurrences field if non-nil, zero value otherwise.
+func (o *Options) GetRenotifyOccurrences() int {
+	if o == nil || o.RenotifyOccurrences == nil {
+		return 0
+	}
+	return *o.RenotifyOccurrences
+}
+
+// GetRenotifyOccurrencesOk returns a tuple with the RenotifyOccurrences field if it's non-nil, zero value otherwise
+// and a boolean to check if the value has been set.
+func (o *Options) GetRenotifyOccurrencesOk() (int, bool) {
+	if o == nil || o.RenotifyOccurrences == nil {
+		return 0, false
+	}
+	return *o.RenotifyOccurrences, true
+}
+
+// HasRenotifyOccurrences returns a boolean if a field has been set.
+func (o *Options) HasRenotifyOccurrences() bool {
+	if o != nil && o.RenotifyOccurrences != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetRenotifyOccurrences allocates a new o.RenotifyOccurrences and returns the pointer to it.
+func (o *Options) SetRenotifyOccurrences(v int) {
+	o.RenotifyOccurrences = &v
+}
+
 // GetRequireFullWindow returns the RequireFullWindow field if non-nil, zero value otherwise.
 func (o *Options) GetRequireFullWindow() bool {
 	if o == nil || o.RequireFullWindow == nil {
@@ -15018,6 +16165,37 @@ func (o *Options) SetRequireFullWindow(v bool) {
 	o.RequireFullWindow = &v
 }
 
+// GetSchedulingOptions returns the SchedulingOptions field if non-nil, zero value otherwise.
+func (o *Options) GetSchedulingOptions() SchedulingOptions {
+	if o == nil || o.SchedulingOptions == nil {
+		return SchedulingOptions{}
+	}
+	return *o.SchedulingOptions
+}
+
+// GetSchedulingOptionsOk returns a tuple with the SchedulingOptions field if it's non-nil, zero value otherwise
+// and a boolean to check if the value has been set.
+func (o *Options) GetSchedulingOptionsOk() (SchedulingOptions, bool) {
+	if o == nil || o.SchedulingOptions == nil {
+		return SchedulingOptions{}, false
+	}
+	return *o.SchedulingOptions, true
+}
+
+// HasSchedulingOptions returns a boolean if a field has been set.
+func (o *Options) HasSchedulingOptions() bool {
+	if o != nil && o.SchedulingOptions != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetSchedulingOptions allocates a new o.SchedulingOptions and returns the pointer to it.
+func (o *Options) SetSchedulingOptions(v SchedulingOptions) {
+	o.SchedulingOptions = &v
+}
+
 // GetThresholds returns the Thresholds field if non-nil, zero value otherwise.
 func (o *Options) GetThresholds() ThresholdCount {
 	if o == nil || o.Thresholds == nil {
@@ -17777,6 +18955,37 @@ func (s *ScatterplotRequests) SetY(v ScatterplotRequest) {
 	s.Y = &v
 }
 
+// GetEvaluationWindow returns the EvaluationWindow field if non-nil, zero value otherwise.
+func (s *SchedulingOptions) GetEvaluationWindow() EvaluationWindow {
+	if s == nil || s.EvaluationWindow == nil {
+		return EvaluationWindow{}
+	}
+	return *s.EvaluationWindow
+}
+
+// GetEvaluationWindowOk returns a tuple with the EvaluationWindow field if it's non-nil, zero value otherwise
+// and a boolean to check if the value has been set.
+func (s *SchedulingOptions) GetEvaluationWindowOk() (EvaluationWindow, bool) {
+	if s == nil || s.EvaluationWindow == nil {
+		return EvaluationWindow{}, false
+	}
+	return *s.EvaluationWindow, true
+}
+
+// HasEvaluationWindow returns a boolean if a field has been set.
+func (s *SchedulingOptions) HasEvaluationWindow() bool {
+	if s != nil && s.EvaluationWindow != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetEvaluationWindow allocates a new s.EvaluationWindow and returns the pointer to it.
+func (s *SchedulingOptions) SetEvaluationWindow(v EvaluationWindow) {
+	s.EvaluationWindow = &v
+}
+
 // GetHeight returns the Height field if non-nil, zero value otherwise.
 func (s *Screenboard) GetHeight() int {
 	if s == nil || s.Height == nil {
@@ -18459,6 +19668,37 @@ func (s *Series) SetUnits(v UnitPair) {
 	s.Units = &v
 }
 
+// GetValue returns the Value field if non-nil, zero value otherwise.
+func (s *SeriesPoint) GetValue() float64 {
+	if s == nil || s.Value == nil {
+		return 0
+	}
+	return *s.Value
+}
+
+// GetValueOk returns a tuple with the Value field if it's non-nil, zero value otherwise
+// and a boolean to check if the value has been set.
+func (s *SeriesPoint) GetValueOk() (float64, bool) {
+	if s == nil || s.Value == nil {
+		return 0, false
+	}
+	return *s.Value, true
+}
+
+// HasValue returns a boolean if a field has been set.
+func (s *SeriesPoint) HasValue() bool {
+	if s != nil && s.Value != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetValue allocates a new s.Value and returns the pointer to it.
+func (s *SeriesPoint) SetValue(v float64) {
+	s.Value = &v
+}
+
 // GetAccount returns the Account field if non-nil, zero value otherwise.
 func (s *ServiceHookSlackRequest) GetAccount() string {
 	if s == nil || s.Account == nil {
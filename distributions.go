@@ -0,0 +1,68 @@
+/*
+ * Datadog API for Go
+ *
+ * Please see the included LICENSE file for licensing information.
+ *
+ * Copyright 2020 by authors and contributors.
+ */
+
+package datadog
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// DistributionPoint is a tuple of [UNIX timestamp, values] of a distribution
+// metric: all the values observed at that time, for the API to aggregate.
+type DistributionPoint struct {
+	Timestamp float64
+	Values    []float64
+}
+
+// MarshalJSON encodes the point as the [timestamp, [values]] tuple the API
+// expects.
+func (p DistributionPoint) MarshalJSON() ([]byte, error) {
+	values := p.Values
+	if values == nil {
+		values = []float64{}
+	}
+	return json.Marshal([]interface{}{p.Timestamp, values})
+}
+
+// UnmarshalJSON decodes a [timestamp, [values]] tuple.
+func (p *DistributionPoint) UnmarshalJSON(data []byte) error {
+	var tuple []json.RawMessage
+	if err := json.Unmarshal(data, &tuple); err != nil {
+		return err
+	}
+	if len(tuple) != 2 {
+		return fmt.Errorf("distribution point must have 2 elements, not %d", len(tuple))
+	}
+	if err := json.Unmarshal(tuple[0], &p.Timestamp); err != nil {
+		return err
+	}
+	return json.Unmarshal(tuple[1], &p.Values)
+}
+
+// DistributionSeries is a distribution metric with its points, as sent to
+// PostDistributionPoints.
+type DistributionSeries struct {
+	Metric *string             `json:"metric,omitempty"`
+	Points []DistributionPoint `json:"points,omitempty"`
+	Type   *string             `json:"type,omitempty"`
+	Host   *string             `json:"host,omitempty"`
+	Tags   []string            `json:"tags,omitempty"`
+}
+
+// reqPostDistributionPoints from /api/v1/distribution_points
+type reqPostDistributionPoints struct {
+	Series []DistributionSeries `json:"series,omitempty"`
+}
+
+// PostDistributionPoints posts the points of distribution metrics, whose
+// percentiles are computed server side across all the hosts sending them.
+func (client *Client) PostDistributionPoints(series []DistributionSeries) error {
+	return client.doJsonRequest("POST", "/v1/distribution_points",
+		reqPostDistributionPoints{Series: series}, nil)
+}
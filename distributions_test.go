@@ -0,0 +1,48 @@
+package datadog_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	dd "github.com/zorkian/go-datadog-api"
+)
+
+func TestPostDistributionPoints(t *testing.T) {
+	var body string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/api/v1/distribution_points", r.URL.Path)
+		data, _ := ioutil.ReadAll(r.Body)
+		body = string(data)
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(`{"status": "ok"}`))
+	}))
+	defer ts.Close()
+
+	client := dd.NewClient("foo", "bar")
+	client.SetBaseUrl(ts.URL)
+
+	err := client.PostDistributionPoints([]dd.DistributionSeries{{
+		Metric: dd.String("payload.size"),
+		Points: []dd.DistributionPoint{{Timestamp: 1600000000, Values: []float64{1, 2.5}}},
+		Tags:   []string{"a:b"},
+	}})
+	assert.Nil(t, err)
+	assert.Equal(t, `{"series":[{"metric":"payload.size","points":[[1600000000,[1,2.5]]],"tags":["a:b"]}]}`, body)
+}
+
+func TestDistributionPointJSON(t *testing.T) {
+	var point dd.DistributionPoint
+	assert.Nil(t, json.Unmarshal([]byte(`[1600000000, [1, 2, 3]]`), &point))
+	assert.Equal(t, dd.DistributionPoint{Timestamp: 1600000000, Values: []float64{1, 2, 3}}, point)
+
+	assert.NotNil(t, json.Unmarshal([]byte(`[1600000000]`), &point))
+
+	data, err := json.Marshal(dd.DistributionPoint{Timestamp: 1})
+	assert.Nil(t, err)
+	assert.Equal(t, `[1,[]]`, string(data))
+}
@@ -9,6 +9,7 @@
 package datadog
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 )
@@ -48,6 +49,27 @@ type Downtime struct {
 	CreatorID   *int        `json:"creator_id,omitempty"`
 	UpdaterID   *int        `json:"updater_id,omitempty"`
 	Type        *int        `json:"downtime_type,omitempty"`
+
+	// unknown holds the fields this library does not know, in lossless mode.
+	unknown map[string]json.RawMessage
+}
+
+func (d *Downtime) setUnknownFields(unknown map[string]json.RawMessage) {
+	d.unknown = unknown
+}
+
+func (d *Downtime) readOnlyFields() []string {
+	return []string{"created", "modified", "creator_id", "updater_id", "org_id", "active", "active_child", "child_id"}
+}
+
+// MarshalJSON encodes the downtime, including the fields kept in lossless mode.
+func (d Downtime) MarshalJSON() ([]byte, error) {
+	type downtime Downtime
+	data, err := json.Marshal(downtime(d))
+	if err != nil {
+		return nil, err
+	}
+	return addUnknownFields(data, d.unknown)
 }
 
 // DowntimeType returns the canonical downtime type classification.
@@ -0,0 +1,323 @@
+/*
+ * Datadog API for Go
+ *
+ * Please see the included LICENSE file for licensing information.
+ *
+ * Copyright 2020 by authors and contributors.
+ */
+
+package datadog
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// MutedWindow is a period during which downtimes mute a monitor group.
+type MutedWindow struct {
+	Start time.Time
+	// End is the zero time if the window never ends.
+	End time.Time
+	// DowntimeIds are the IDs of the downtimes making up the window.
+	DowntimeIds []int
+}
+
+// Contains returns true if t is within the window.
+func (w MutedWindow) Contains(t time.Time) bool {
+	return !t.Before(w.Start) && (w.End.IsZero() || t.Before(w.End))
+}
+
+// maxDowntimeOccurrences bounds the occurrences computed for one recurring
+// downtime, in case of a recurrence that never ends.
+const maxDowntimeOccurrences = 10000
+
+// DowntimesForMonitor returns the downtimes that apply to a monitor, whatever
+// their schedule and scope: the ones for its ID, the ones for monitor tags it
+// has all of, and the ones for all monitors. Disabled downtimes are left out.
+func DowntimesForMonitor(monitor *Monitor, downtimes []Downtime) []Downtime {
+	tags := make(map[string]bool, len(monitor.Tags))
+	for _, tag := range monitor.Tags {
+		tags[tag] = true
+	}
+
+	var out []Downtime
+	for _, downtime := range downtimes {
+		if downtime.GetDisabled() {
+			continue
+		}
+		if downtime.HasMonitorId() {
+			if downtime.GetMonitorId() == monitor.GetId() {
+				out = append(out, downtime)
+			}
+			continue
+		}
+		matches := true
+		for _, tag := range downtime.MonitorTags {
+			matches = matches && (tag == "*" || tags[tag])
+		}
+		if matches {
+			out = append(out, downtime)
+		}
+	}
+	return out
+}
+
+// MonitorMutedWindows works out when each group of a monitor is muted by
+// downtimes between from and to. Groups are the keys of Monitor.State.Groups,
+// e.g. "host:foo,env:prod", or "*" for a monitor without groups, which only
+// downtimes scoped to "*" mute. Recurring downtimes are expanded into their
+// occurrences, and overlapping windows are merged. Groups never muted are left
+// out of the result.
+func MonitorMutedWindows(monitor *Monitor, downtimes []Downtime, from, to time.Time) map[string][]MutedWindow {
+	groups := make([]string, 0, len(monitor.State.Groups))
+	for group := range monitor.State.Groups {
+		groups = append(groups, group)
+	}
+	if len(groups) == 0 {
+		groups = append(groups, "*")
+	}
+
+	windows := make(map[string][]MutedWindow)
+	for _, downtime := range DowntimesForMonitor(monitor, downtimes) {
+		occurrences := downtimeOccurrences(&downtime, from, to)
+		if len(occurrences) == 0 {
+			continue
+		}
+		for _, group := range groups {
+			if downtimeScopeMatches(downtime.Scope, group) {
+				windows[group] = append(windows[group], occurrences...)
+			}
+		}
+	}
+	for group := range windows {
+		windows[group] = mergeMutedWindows(windows[group])
+	}
+	return windows
+}
+
+// MutedUntil returns the end of the window muting at t, the zero time
+// meaning forever, or false if no window mutes at t.
+func MutedUntil(windows []MutedWindow, t time.Time) (time.Time, bool) {
+	for _, window := range windows {
+		if window.Contains(t) {
+			return window.End, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// downtimeScopeMatches returns true if a group has all the tags of a downtime
+// scope. A scope of "*" matches every group.
+func downtimeScopeMatches(scope []string, group string) bool {
+	tags := make(map[string]bool)
+	for _, tag := range strings.Split(group, ",") {
+		tags[strings.TrimSpace(tag)] = true
+	}
+	for _, tag := range scope {
+		if tag != "*" && !tags[tag] {
+			return false
+		}
+	}
+	return true
+}
+
+// downtimeOccurrences returns the windows of a downtime overlapping from and
+// to. A canceled downtime ends at the time it was canceled.
+func downtimeOccurrences(downtime *Downtime, from, to time.Time) []MutedWindow {
+	if !downtime.HasStart() {
+		return nil
+	}
+	start := time.Unix(int64(downtime.GetStart()), 0)
+	var end, canceled time.Time
+	if downtime.HasEnd() {
+		end = time.Unix(int64(downtime.GetEnd()), 0)
+	}
+	if downtime.HasCanceled() {
+		canceled = time.Unix(int64(downtime.GetCanceled()), 0)
+	}
+
+	var out []MutedWindow
+	add := func(start, end time.Time) {
+		if !canceled.IsZero() && (end.IsZero() || end.After(canceled)) {
+			end = canceled
+		}
+		if start.Before(to) && (end.IsZero() || end.After(from)) && (end.IsZero() || start.Before(end)) {
+			out = append(out, MutedWindow{Start: start, End: end, DowntimeIds: []int{downtime.GetId()}})
+		}
+	}
+
+	recurrence := downtime.Recurrence
+	if recurrence == nil || end.IsZero() {
+		add(start, end)
+		return out
+	}
+
+	location, err := time.LoadLocation(downtime.GetTimezone())
+	if err != nil {
+		location = time.UTC
+	}
+	duration := end.Sub(start)
+	var until time.Time
+	if recurrence.HasUntilDate() {
+		until = time.Unix(int64(recurrence.GetUntilDate()), 0)
+	}
+	// Occurrences starting up to duration before from still overlap it.
+	starts, skipped := recurrenceStarts(recurrence, start.In(location), from.Add(-duration), to)
+	for i, occurrence := range starts {
+		if (!until.IsZero() && occurrence.After(until)) || !occurrence.Before(to) {
+			break
+		}
+		if recurrence.HasUntilOccurrences() && skipped+i >= recurrence.GetUntilOccurrences() {
+			break
+		}
+		add(occurrence, occurrence.Add(duration))
+	}
+	return out
+}
+
+// downtimeWeekDays maps the week days of recurrences to their offset from
+// Monday.
+var downtimeWeekDays = map[string]int{"Mon": 0, "Tue": 1, "Wed": 2, "Thu": 3, "Fri": 4, "Sat": 5, "Sun": 6}
+
+// recurrenceStarts returns the start times of the occurrences of a
+// recurrence beginning at start, in order, from the first one at or after
+// from up to the first one at or after to, along with the number of
+// occurrences before from.
+func recurrenceStarts(recurrence *Recurrence, start, from, to time.Time) ([]time.Time, int) {
+	period := recurrence.GetPeriod()
+	if period < 1 {
+		period = 1
+	}
+	year, month, day := start.Date()
+	hour, min, sec := start.Clock()
+	atDays := func(days int) time.Time {
+		return time.Date(year, month, day+days, hour, min, sec, 0, start.Location())
+	}
+	// An occurrence falling on a day a month doesn't have, like the 31st,
+	// falls on its last day instead of spilling over into the next month.
+	atMonths := func(months int) time.Time {
+		first := time.Date(year, month+time.Month(months), 1, hour, min, sec, 0, start.Location())
+		last := time.Date(first.Year(), first.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+		if day < last {
+			last = day
+		}
+		return time.Date(first.Year(), first.Month(), last, hour, min, sec, 0, start.Location())
+	}
+
+	var offsets []int
+	if recurrence.GetType() == "weeks" {
+		for _, name := range recurrence.WeekDays {
+			if offset, ok := downtimeWeekDays[name]; ok {
+				offsets = append(offsets, offset)
+			}
+		}
+		sort.Ints(offsets)
+	}
+	monday := (int(start.Weekday()) + 6) % 7
+
+	// Skip the periods before from, so that maxDowntimeOccurrences only
+	// bounds the occurrences from there on. The skip stops a period short
+	// since days may be shorter than 24 hours across daylight saving time
+	// changes.
+	first, skipped := 0, 0
+	if from.After(start) {
+		elapsed := from.Sub(start)
+		fromYear, fromMonth, _ := from.In(start.Location()).Date()
+		switch recurrence.GetType() {
+		case "days":
+			first = int(elapsed.Hours()/24) / period
+		case "weeks":
+			first = int(elapsed.Hours()/(24*7)) / period
+		case "months":
+			first = ((fromYear-year)*12 + int(fromMonth-month)) / period
+		case "years":
+			first = (fromYear - year) / period
+		}
+		if first > 0 {
+			first--
+		}
+		skipped = first
+		if len(offsets) > 0 && first > 0 {
+			skipped = (first - 1) * len(offsets)
+			for _, offset := range offsets {
+				if offset >= monday {
+					skipped++
+				}
+			}
+		}
+	}
+
+	var starts []time.Time
+	add := func(occurrence time.Time) {
+		if occurrence.Before(from) {
+			skipped++
+			return
+		}
+		starts = append(starts, occurrence)
+	}
+	for i := first; len(starts) < maxDowntimeOccurrences; i++ {
+		switch recurrence.GetType() {
+		case "days":
+			add(atDays(i * period))
+		case "weeks":
+			if len(offsets) == 0 {
+				add(atDays(7 * i * period))
+				break
+			}
+			// Occurrences fall on the week days of every period-th week,
+			// counting from the week of start.
+			for _, offset := range offsets {
+				if i > 0 || offset >= monday {
+					add(atDays(7*i*period + offset - monday))
+				}
+			}
+		case "months":
+			add(atMonths(i * period))
+		case "years":
+			add(atMonths(12 * i * period))
+		default:
+			return []time.Time{start}, 0
+		}
+		if n := len(starts); n > 0 && !starts[n-1].Before(to) {
+			break
+		}
+	}
+	return starts, skipped
+}
+
+// mergeMutedWindows sorts windows and merges the overlapping ones.
+func mergeMutedWindows(windows []MutedWindow) []MutedWindow {
+	sort.Slice(windows, func(i, j int) bool {
+		return windows[i].Start.Before(windows[j].Start)
+	})
+	var merged []MutedWindow
+	for _, window := range windows {
+		if n := len(merged); n > 0 {
+			last := &merged[n-1]
+			if last.End.IsZero() || !window.Start.After(last.End) {
+				if !last.End.IsZero() && (window.End.IsZero() || window.End.After(last.End)) {
+					last.End = window.End
+				}
+				last.DowntimeIds = appendUniqueInt(last.DowntimeIds, window.DowntimeIds...)
+				continue
+			}
+		}
+		window.DowntimeIds = append([]int(nil), window.DowntimeIds...)
+		merged = append(merged, window)
+	}
+	return merged
+}
+
+func appendUniqueInt(list []int, values ...int) []int {
+	for _, value := range values {
+		found := false
+		for _, existing := range list {
+			found = found || existing == value
+		}
+		if !found {
+			list = append(list, value)
+		}
+	}
+	return list
+}
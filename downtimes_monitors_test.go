@@ -0,0 +1,173 @@
+package datadog_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	dd "github.com/zorkian/go-datadog-api"
+)
+
+func TestDowntimesForMonitor(t *testing.T) {
+	monitor := &dd.Monitor{Id: dd.Int(1), Tags: []string{"team:web", "env:prod"}}
+	downtimes := []dd.Downtime{
+		{Id: dd.Int(10), MonitorId: dd.Int(1)},
+		{Id: dd.Int(11), MonitorId: dd.Int(2)},
+		{Id: dd.Int(12), MonitorTags: []string{"team:web"}},
+		{Id: dd.Int(13), MonitorTags: []string{"team:web", "env:staging"}},
+		{Id: dd.Int(14), MonitorTags: []string{"*"}},
+		{Id: dd.Int(15)},
+		{Id: dd.Int(16), Disabled: dd.Bool(true)},
+	}
+
+	var ids []int
+	for _, downtime := range dd.DowntimesForMonitor(monitor, downtimes) {
+		ids = append(ids, downtime.GetId())
+	}
+	assert.Equal(t, []int{10, 12, 14, 15}, ids)
+}
+
+func TestMonitorMutedWindows(t *testing.T) {
+	day := func(d, hour int) time.Time {
+		return time.Date(2020, 6, d, hour, 0, 0, 0, time.UTC)
+	}
+	unix := func(t time.Time) *int {
+		return dd.Int(int(t.Unix()))
+	}
+	monitor := &dd.Monitor{
+		Id: dd.Int(1),
+		State: dd.State{Groups: map[string]dd.GroupData{
+			"host:a,env:prod": {},
+			"host:b,env:prod": {},
+			"host:c,env:dev":  {},
+		}},
+	}
+	downtimes := []dd.Downtime{
+		// Every host in prod, June 1st from 10:00 to 12:00.
+		{Id: dd.Int(1), Scope: []string{"env:prod"}, Start: unix(day(1, 10)), End: unix(day(1, 12))},
+		// host:a from June 1st 11:00, overlapping the first one, forever.
+		{Id: dd.Int(2), Scope: []string{"host:a"}, Start: unix(day(1, 11))},
+		// Everything, every other day from 22:00 to 23:00, three times.
+		{Id: dd.Int(3), Scope: []string{"*"}, Start: unix(day(1, 22)), End: unix(day(1, 23)),
+			Recurrence: &dd.Recurrence{Type: dd.String("days"), Period: dd.Int(2), UntilOccurrences: dd.Int(3)}},
+		// host:c on Mondays and Wednesdays from 08:00 to 09:00, canceled on
+		// June 10th. June 1st 2020 was a Monday.
+		{Id: dd.Int(4), Scope: []string{"host:c"}, Start: unix(day(1, 8)), End: unix(day(1, 9)),
+			Canceled: unix(day(10, 0)), Timezone: dd.String("UTC"),
+			Recurrence: &dd.Recurrence{Type: dd.String("weeks"), Period: dd.Int(1), WeekDays: []string{"Wed", "Mon"}}},
+		// Another monitor.
+		{Id: dd.Int(5), MonitorId: dd.Int(2), Scope: []string{"*"}, Start: unix(day(1, 0))},
+	}
+
+	windows := dd.MonitorMutedWindows(monitor, downtimes, day(1, 0), day(30, 0))
+	assert.Equal(t, map[string][]dd.MutedWindow{
+		"host:a,env:prod": {
+			{Start: day(1, 10), DowntimeIds: []int{1, 2, 3}},
+		},
+		"host:b,env:prod": {
+			{Start: day(1, 10), End: day(1, 12), DowntimeIds: []int{1}},
+			{Start: day(1, 22), End: day(1, 23), DowntimeIds: []int{3}},
+			{Start: day(3, 22), End: day(3, 23), DowntimeIds: []int{3}},
+			{Start: day(5, 22), End: day(5, 23), DowntimeIds: []int{3}},
+		},
+		"host:c,env:dev": {
+			{Start: day(1, 8), End: day(1, 9), DowntimeIds: []int{4}},
+			{Start: day(1, 22), End: day(1, 23), DowntimeIds: []int{3}},
+			{Start: day(3, 8), End: day(3, 9), DowntimeIds: []int{4}},
+			{Start: day(3, 22), End: day(3, 23), DowntimeIds: []int{3}},
+			{Start: day(5, 22), End: day(5, 23), DowntimeIds: []int{3}},
+			{Start: day(8, 8), End: day(8, 9), DowntimeIds: []int{4}},
+		},
+	}, normalizeWindows(windows))
+
+	until, muted := dd.MutedUntil(windows["host:b,env:prod"], day(1, 11))
+	assert.True(t, muted)
+	assert.True(t, until.Equal(day(1, 12)))
+	until, muted = dd.MutedUntil(windows["host:a,env:prod"], day(20, 0))
+	assert.True(t, muted)
+	assert.True(t, until.IsZero())
+	_, muted = dd.MutedUntil(windows["host:c,env:dev"], day(2, 8))
+	assert.False(t, muted)
+
+	// A monitor without groups is only muted by downtimes for all scopes.
+	simple := dd.MonitorMutedWindows(&dd.Monitor{Id: dd.Int(1)}, downtimes, day(1, 0), day(2, 0))
+	assert.Equal(t, []string{"*"}, mapKeys(simple))
+	assert.Equal(t, []int{3}, simple["*"][0].DowntimeIds)
+}
+
+// normalizeWindows converts the times of windows to UTC so they compare
+// equal.
+func normalizeWindows(windows map[string][]dd.MutedWindow) map[string][]dd.MutedWindow {
+	for _, list := range windows {
+		for i := range list {
+			list[i].Start = list[i].Start.UTC()
+			if !list[i].End.IsZero() {
+				list[i].End = list[i].End.UTC()
+			}
+		}
+	}
+	return windows
+}
+
+func mapKeys(windows map[string][]dd.MutedWindow) []string {
+	var keys []string
+	for key := range windows {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func TestMonitorMutedWindowsRecurrences(t *testing.T) {
+	at := func(year int, month time.Month, d, hour int) time.Time {
+		return time.Date(year, month, d, hour, 0, 0, 0, time.UTC)
+	}
+	unix := func(t time.Time) *int {
+		return dd.Int(int(t.Unix()))
+	}
+	muted := func(downtime dd.Downtime, from, to time.Time) []dd.MutedWindow {
+		downtime.Id, downtime.Scope = dd.Int(1), []string{"*"}
+		windows := dd.MonitorMutedWindows(&dd.Monitor{Id: dd.Int(1)}, []dd.Downtime{downtime}, from, to)
+		return normalizeWindows(windows)["*"]
+	}
+
+	// A daily downtime that began long enough ago to have had more
+	// occurrences than are ever computed still applies today.
+	daily := dd.Downtime{Start: unix(at(1980, 1, 1, 22)), End: unix(at(1980, 1, 1, 23)),
+		Recurrence: &dd.Recurrence{Type: dd.String("days"), Period: dd.Int(1)}}
+	assert.Equal(t, []dd.MutedWindow{
+		{Start: at(2020, 6, 1, 22), End: at(2020, 6, 1, 23), DowntimeIds: []int{1}},
+		{Start: at(2020, 6, 2, 22), End: at(2020, 6, 2, 23), DowntimeIds: []int{1}},
+	}, muted(daily, at(2020, 6, 1, 0), at(2020, 6, 3, 0)))
+
+	// An occurrence started before from still counts while it lasts.
+	assert.Equal(t, []dd.MutedWindow{
+		{Start: at(2020, 6, 1, 22), End: at(2020, 6, 1, 23), DowntimeIds: []int{1}},
+	}, muted(daily, time.Date(2020, 6, 1, 22, 30, 0, 0, time.UTC), at(2020, 6, 2, 0)))
+
+	// Occurrences skipped before from count towards the until occurrences.
+	weekly := dd.Downtime{Start: unix(at(2020, 6, 1, 8)), End: unix(at(2020, 6, 1, 9)),
+		Recurrence: &dd.Recurrence{Type: dd.String("weeks"), Period: dd.Int(1),
+			WeekDays: []string{"Mon", "Wed"}, UntilOccurrences: dd.Int(7)}}
+	assert.Equal(t, []dd.MutedWindow{
+		{Start: at(2020, 6, 15, 8), End: at(2020, 6, 15, 9), DowntimeIds: []int{1}},
+		{Start: at(2020, 6, 17, 8), End: at(2020, 6, 17, 9), DowntimeIds: []int{1}},
+		{Start: at(2020, 6, 22, 8), End: at(2020, 6, 22, 9), DowntimeIds: []int{1}},
+	}, muted(weekly, at(2020, 6, 14, 0), at(2020, 7, 1, 0)))
+
+	// Monthly occurrences on days some months don't have fall on their
+	// last day.
+	monthly := dd.Downtime{Start: unix(at(2020, 1, 31, 8)), End: unix(at(2020, 1, 31, 9)),
+		Recurrence: &dd.Recurrence{Type: dd.String("months"), Period: dd.Int(1)}}
+	assert.Equal(t, []dd.MutedWindow{
+		{Start: at(2020, 1, 31, 8), End: at(2020, 1, 31, 9), DowntimeIds: []int{1}},
+		{Start: at(2020, 2, 29, 8), End: at(2020, 2, 29, 9), DowntimeIds: []int{1}},
+		{Start: at(2020, 3, 31, 8), End: at(2020, 3, 31, 9), DowntimeIds: []int{1}},
+		{Start: at(2020, 4, 30, 8), End: at(2020, 4, 30, 9), DowntimeIds: []int{1}},
+	}, muted(monthly, at(2020, 1, 1, 0), at(2020, 5, 1, 0)))
+
+	yearly := dd.Downtime{Start: unix(at(2020, 2, 29, 8)), End: unix(at(2020, 2, 29, 9)),
+		Recurrence: &dd.Recurrence{Type: dd.String("years"), Period: dd.Int(1)}}
+	assert.Equal(t, []dd.MutedWindow{
+		{Start: at(2021, 2, 28, 8), End: at(2021, 2, 28, 9), DowntimeIds: []int{1}},
+	}, muted(yearly, at(2021, 1, 1, 0), at(2022, 1, 1, 0)))
+}
@@ -0,0 +1,108 @@
+/*
+ * Datadog API for Go
+ *
+ * Please see the included LICENSE file for licensing information.
+ *
+ * Copyright 2020 by authors and contributors.
+ */
+
+package datadog
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// APIError is returned when the Datadog API answers a request with a non-2xx
+// HTTP status code.
+type APIError struct {
+	// StatusCode is the HTTP status code of the response, e.g. 404.
+	StatusCode int
+	// Status is the HTTP status line of the response, e.g. "404 Not Found".
+	Status string
+	// Method and Path identify the request that failed. Path never contains
+	// the query string, so it is free of credentials.
+	Method string
+	Path   string
+	// Errors holds the messages of the "errors" array Datadog puts in most
+	// error responses.
+	Errors []string
+	// Body is the raw body of the response.
+	Body string
+	// RateLimit holds the X-RateLimit headers of the response, if any.
+	RateLimit RateLimit
+	// RetryAfter is how long the API asked to wait before retrying a request
+	// that was rate limited. It is 0 if the API did not say.
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API error %s: %s", e.Status, e.Body)
+}
+
+// newAPIError builds an APIError out of a failed request and its response
+// body.
+func newAPIError(req *http.Request, resp *http.Response, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Method:     req.Method,
+		Path:       req.URL.Path,
+		Body:       string(body),
+		RateLimit: RateLimit{
+			Limit:     resp.Header.Get("X-RateLimit-Limit"),
+			Period:    resp.Header.Get("X-RateLimit-Period"),
+			Reset:     resp.Header.Get("X-RateLimit-Reset"),
+			Remaining: resp.Header.Get("X-RateLimit-Remaining"),
+		},
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		apiErr.RetryAfter, _ = retryAfter(resp, time.Now())
+	}
+
+	var out struct {
+		Errors []string `json:"errors"`
+	}
+	// Not every error response is JSON, in which case only Body is set.
+	if err := json.Unmarshal(body, &out); err == nil {
+		apiErr.Errors = out.Errors
+	}
+	return apiErr
+}
+
+// statusCode returns the HTTP status code carried by err, or 0 if err is not
+// an *APIError.
+func statusCode(err error) int {
+	if apiErr, ok := err.(*APIError); ok {
+		return apiErr.StatusCode
+	}
+	return 0
+}
+
+// IsNotFound returns true if err is an API error with a 404 status code.
+func IsNotFound(err error) bool {
+	return statusCode(err) == http.StatusNotFound
+}
+
+// IsUnauthorized returns true if err is an API error with a 401 status code,
+// which Datadog answers when the keys are missing or invalid.
+func IsUnauthorized(err error) bool {
+	return statusCode(err) == http.StatusUnauthorized
+}
+
+// IsForbidden returns true if err is an API error with a 403 status code.
+func IsForbidden(err error) bool {
+	return statusCode(err) == http.StatusForbidden
+}
+
+// IsRateLimited returns true if err is an API error with a 429 status code, or
+// a *RateLimitExceededError returned by a client throttling itself.
+func IsRateLimited(err error) bool {
+	if _, ok := err.(*RateLimitExceededError); ok {
+		return true
+	}
+	return statusCode(err) == http.StatusTooManyRequests
+}
@@ -202,15 +202,16 @@ func getTestMonitor() *datadog.Monitor {
 		IncludeTags:       datadog.Bool(false),
 	}
 
-	return &datadog.Monitor{
-		Message:      datadog.String("Test message"),
-		Query:        datadog.String("avg(last_15m):avg:system.disk.in_use{*} by {host,device} > 0.8"),
-		Name:         datadog.String("Test monitor"),
-		Options:      o,
-		Type:         datadog.String("metric alert"),
-		Tags:         make([]string, 0),
-		OverallState: datadog.String("No Data"),
+	m := &datadog.Monitor{
+		Message: datadog.String("Test message"),
+		Query:   datadog.String("avg(last_15m):avg:system.disk.in_use{*} by {host,device} > 0.8"),
+		Name:    datadog.String("Test monitor"),
+		Options: o,
+		Type:    datadog.String("metric alert"),
+		Tags:    make([]string, 0),
 	}
+	m.SetOverallState(datadog.MonitorStatusNoData)
+	return m
 }
 
 func getTestMonitorWithTags() *datadog.Monitor {
@@ -0,0 +1,118 @@
+/*
+ * Datadog API for Go
+ *
+ * Please see the included LICENSE file for licensing information.
+ *
+ * Copyright 2020 by authors and contributors.
+ */
+
+package datadog
+
+import (
+	"net/http"
+	"net/http/httputil"
+	"time"
+)
+
+// Logger receives structured log records from the client. Its methods take a
+// message followed by alternating keys and values, so a *slog.Logger can be
+// used as is.
+type Logger interface {
+	Debug(msg string, args ...interface{})
+	Info(msg string, args ...interface{})
+	Warn(msg string, args ...interface{})
+	Error(msg string, args ...interface{})
+}
+
+// WithLogger sets the logger of the client.
+func WithLogger(logger Logger) ClientOption {
+	return func(c *Client) {
+		c.Logger = logger
+	}
+}
+
+// WithDebug makes the client log the requests and responses it exchanges,
+// bodies included, with keys redacted.
+func WithDebug(debug bool) ClientOption {
+	return func(c *Client) {
+		c.Debug = debug
+	}
+}
+
+// logRequest records the outcome of one attempt at sending req with keys,
+// which are redacted from errors. Transport errors are logged as errors,
+// failing status codes as warnings, and the rest at the debug level.
+func (client *Client) logRequest(req *http.Request, keys Credentials, resp *http.Response, err error, attempt int, latency time.Duration) {
+	if client.Logger == nil {
+		return
+	}
+	args := []interface{}{
+		"method", req.Method,
+		"path", req.URL.Path,
+		"attempt", attempt,
+		"latency", latency,
+	}
+	if err != nil {
+		args = append(args, "error", keys.redactError(err).Error())
+		client.Logger.Error("datadog request failed", args...)
+		return
+	}
+
+	args = append(args, "status", resp.StatusCode)
+	if remaining := resp.Header.Get("X-RateLimit-Remaining"); remaining != "" {
+		args = append(args,
+			"ratelimit_limit", resp.Header.Get("X-RateLimit-Limit"),
+			"ratelimit_remaining", remaining,
+			"ratelimit_reset", resp.Header.Get("X-RateLimit-Reset"),
+			"ratelimit_period", resp.Header.Get("X-RateLimit-Period"),
+		)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		client.Logger.Warn("datadog request", args...)
+	} else {
+		client.Logger.Debug("datadog request", args...)
+	}
+}
+
+// logRateLimited records that req was rate limited on the given attempt, and
+// that the client waits for wait before sending it again.
+func (client *Client) logRateLimited(req *http.Request, attempt int, wait time.Duration) {
+	if client.Logger == nil {
+		return
+	}
+	client.Logger.Info("datadog request rate limited",
+		"method", req.Method,
+		"path", req.URL.Path,
+		"attempt", attempt,
+		"wait", wait,
+	)
+}
+
+// dumpRequest logs req, headers and body included, in debug mode. keys are
+// the credentials of the request, redacted from the dump.
+func (client *Client) dumpRequest(req *http.Request, keys Credentials) {
+	if client.Logger == nil || !client.Debug {
+		return
+	}
+	// Compressed bodies are left out, as they are not readable.
+	dump, err := httputil.DumpRequestOut(req, req.Header.Get("Content-Encoding") == "")
+	if err != nil {
+		client.Logger.Debug("datadog request dump failed", "error", keys.redactError(err).Error())
+		return
+	}
+	client.Logger.Debug("datadog request dump", "dump", keys.redactString(string(dump)))
+}
+
+// dumpResponse logs resp, headers and body included, in debug mode, with
+// keys redacted.
+func (client *Client) dumpResponse(resp *http.Response, keys Credentials) {
+	if client.Logger == nil || !client.Debug {
+		return
+	}
+	dump, err := httputil.DumpResponse(resp, true)
+	if err != nil {
+		client.Logger.Debug("datadog response dump failed", "error", keys.redactError(err).Error())
+		return
+	}
+	client.Logger.Debug("datadog response dump", "dump", keys.redactString(string(dump)))
+}
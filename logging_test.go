@@ -0,0 +1,96 @@
+package datadog
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type logRecord struct {
+	level, msg string
+	args       map[string]interface{}
+}
+
+// testLogger records log records in memory.
+type testLogger struct {
+	m       sync.Mutex
+	records []logRecord
+}
+
+func (l *testLogger) log(level, msg string, args []interface{}) {
+	l.m.Lock()
+	defer l.m.Unlock()
+	record := logRecord{level: level, msg: msg, args: map[string]interface{}{}}
+	for i := 0; i+1 < len(args); i += 2 {
+		record.args[fmt.Sprint(args[i])] = args[i+1]
+	}
+	l.records = append(l.records, record)
+}
+
+func (l *testLogger) Debug(msg string, args ...interface{}) { l.log("debug", msg, args) }
+func (l *testLogger) Info(msg string, args ...interface{})  { l.log("info", msg, args) }
+func (l *testLogger) Warn(msg string, args ...interface{})  { l.log("warn", msg, args) }
+func (l *testLogger) Error(msg string, args ...interface{}) { l.log("error", msg, args) }
+
+func TestLogging(t *testing.T) {
+	t.Run("Requests are logged", func(t *testing.T) {
+		s := makeTestServer(http.StatusOK, `{"status": "ok"}`)
+		defer s.Close()
+		logger := &testLogger{}
+		c := NewClientWithOptions(WithKeys("sample_api_key", "sample_app_key"), WithBaseUrl(s.URL), WithLogger(logger))
+
+		assert.Nil(t, c.doJsonRequest("GET", "/v1/something", nil, nil))
+		if assert.Len(t, logger.records, 1) {
+			record := logger.records[0]
+			assert.Equal(t, "debug", record.level)
+			assert.Equal(t, "GET", record.args["method"])
+			assert.Equal(t, "/api/v1/something", record.args["path"])
+			assert.Equal(t, http.StatusOK, record.args["status"])
+			assert.Equal(t, 1, record.args["attempt"])
+			assert.IsType(t, time.Duration(0), record.args["latency"])
+		}
+	})
+	t.Run("Retries are logged as warnings", func(t *testing.T) {
+		s := makeTestServer(http.StatusBadGateway, "")
+		defer s.Close()
+		logger := &testLogger{}
+		c := NewClientWithOptions(WithBaseUrl(s.URL), WithLogger(logger),
+			WithRetryPolicy(&DefaultRetryPolicy{InitialInterval: 10 * time.Millisecond}),
+			WithRetryTimeout(100*time.Millisecond))
+
+		assert.NotNil(t, c.doJsonRequest("GET", "/v1/something", nil, nil))
+		assert.True(t, len(logger.records) > 1)
+		for i, record := range logger.records {
+			assert.Equal(t, "warn", record.level)
+			assert.Equal(t, i+1, record.args["attempt"])
+			assert.Equal(t, http.StatusBadGateway, record.args["status"])
+		}
+	})
+	t.Run("Debug mode dumps redacted requests and responses", func(t *testing.T) {
+		s := makeTestServer(http.StatusOK, `{"status": "ok"}`)
+		defer s.Close()
+		logger := &testLogger{}
+		c := NewClientWithOptions(WithKeys("sample_api_key", "sample_app_key"), WithBaseUrl(s.URL),
+			WithLogger(logger), WithDebug(true))
+
+		assert.Nil(t, c.doJsonRequest("POST", "/v1/something", map[string]string{"metric": "foo.bar"}, nil))
+		var dumps []string
+		for _, record := range logger.records {
+			if strings.HasSuffix(record.msg, "dump") {
+				dumps = append(dumps, record.args["dump"].(string))
+			}
+		}
+		if assert.Len(t, dumps, 2) {
+			assert.Contains(t, dumps[0], "POST /api/v1/something")
+			assert.Contains(t, dumps[0], "Dd-Api-Key: redacted")
+			assert.Contains(t, dumps[0], `{"metric":"foo.bar"}`)
+			assert.NotContains(t, dumps[0], "sample_")
+			assert.Contains(t, dumps[1], `{"status": "ok"}`)
+		}
+	})
+}
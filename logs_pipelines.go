@@ -9,6 +9,7 @@
 package datadog
 
 import (
+	"encoding/json"
 	"fmt"
 )
 
@@ -25,6 +26,27 @@ type LogsPipeline struct {
 	IsReadOnly *bool                `json:"is_read_only,omitempty"`
 	Filter     *FilterConfiguration `json:"filter"`
 	Processors []LogsProcessor      `json:"processors,omitempty"`
+
+	// unknown holds the fields this library does not know, in lossless mode.
+	unknown map[string]json.RawMessage
+}
+
+func (l *LogsPipeline) setUnknownFields(unknown map[string]json.RawMessage) {
+	l.unknown = unknown
+}
+
+func (l *LogsPipeline) readOnlyFields() []string {
+	return []string{"id", "type", "is_read_only", "created_at", "modified_at"}
+}
+
+// MarshalJSON encodes the logs pipeline, including the fields kept in lossless mode.
+func (l LogsPipeline) MarshalJSON() ([]byte, error) {
+	type logsPipeline LogsPipeline
+	data, err := json.Marshal(logsPipeline(l))
+	if err != nil {
+		return nil, err
+	}
+	return addUnknownFields(data, l.unknown)
 }
 
 // FilterConfiguration struct to represent the json object of filter configuration.
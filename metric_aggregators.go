@@ -0,0 +1,389 @@
+/*
+ * Datadog API for Go
+ *
+ * Please see the included LICENSE file for licensing information.
+ *
+ * Copyright 2020 by authors and contributors.
+ */
+
+package datadog
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Types of the metrics sent with PostMetrics, and of the distributions sent
+// with PostDistributionPoints.
+const (
+	MetricTypeGauge        = "gauge"
+	MetricTypeRate         = "rate"
+	MetricTypeCount        = "count"
+	MetricTypeDistribution = "distribution"
+)
+
+// DefaultHistogramPercentiles are the percentiles reported by a Histogram
+// created without any.
+var DefaultHistogramPercentiles = []float64{0.95}
+
+// Aggregator is a metric aggregated in process. FlushSeries returns what was
+// aggregated since the previous flush, the metrics ready for PostMetrics and
+// the distributions ready for PostDistributionPoints, and starts a new flush
+// window. It returns nothing if no value was recorded in the window.
+type Aggregator interface {
+	FlushSeries(now time.Time) ([]Metric, []DistributionSeries)
+}
+
+// CollectMetrics flushes aggregators and returns all their metrics and
+// distributions.
+func CollectMetrics(now time.Time, aggregators ...Aggregator) ([]Metric, []DistributionSeries) {
+	var metrics []Metric
+	var distributions []DistributionSeries
+	for _, aggregator := range aggregators {
+		m, d := aggregator.FlushSeries(now)
+		metrics = append(metrics, m...)
+		distributions = append(distributions, d...)
+	}
+	return metrics, distributions
+}
+
+// aggregator holds what every in-process metric has in common: its name,
+// host and tags, and the start of its current flush window.
+type aggregator struct {
+	name string
+	host string
+	tags []string
+
+	// mu protects the window and the values of the metric embedding it.
+	mu    sync.Mutex
+	start time.Time
+}
+
+func newAggregator(name, host string, tags []string) aggregator {
+	return aggregator{
+		name:  name,
+		host:  host,
+		tags:  append([]string(nil), tags...),
+		start: time.Now(),
+	}
+}
+
+// flushWindow is a flush window that is over.
+type flushWindow struct {
+	start time.Time
+	// seconds is the exact length of the window, for rates to be computed
+	// over.
+	seconds float64
+}
+
+// interval returns the length of the window in whole seconds, at least 1,
+// as reported in Metric.Interval.
+func (w flushWindow) interval() int {
+	interval := int(w.seconds + 0.5)
+	if interval < 1 {
+		interval = 1
+	}
+	return interval
+}
+
+// rate returns value per second over the window.
+func (w flushWindow) rate(value float64) float64 {
+	if w.seconds <= 0 {
+		return value
+	}
+	return value / w.seconds
+}
+
+// nextWindow starts a new flush window at now and returns the window just
+// over. It must be called with mu held.
+func (a *aggregator) nextWindow(now time.Time) flushWindow {
+	start := a.start
+	a.start = now
+	return flushWindow{start: start, seconds: now.Sub(start).Seconds()}
+}
+
+// metric returns a metric of the aggregator with a single point at the start
+// of window.
+func (a *aggregator) metric(suffix, metricType string, window flushWindow, value float64) Metric {
+	m := Metric{
+		Metric: String(a.name + suffix),
+		Points: []DataPoint{{Float64(float64(window.start.Unix())), Float64(value)}},
+		Type:   String(metricType),
+		Tags:   append([]string(nil), a.tags...),
+	}
+	if a.host != "" {
+		m.Host = String(a.host)
+	}
+	if metricType != MetricTypeGauge {
+		m.Interval = Int(window.interval())
+	}
+	return m
+}
+
+// Counter counts occurrences, e.g. requests served. It is flushed as a count
+// of the occurrences during the flush window.
+type Counter struct {
+	aggregator
+	value float64
+	set   bool
+}
+
+// NewCounter returns a Counter for the metric name, reported for host, or
+// without a host if it is empty, with tags.
+func NewCounter(name, host string, tags []string) *Counter {
+	return &Counter{aggregator: newAggregator(name, host, tags)}
+}
+
+// Add adds delta to the counter.
+func (c *Counter) Add(delta float64) {
+	c.mu.Lock()
+	c.value += delta
+	c.set = true
+	c.mu.Unlock()
+}
+
+// Inc adds one to the counter.
+func (c *Counter) Inc() {
+	c.Add(1)
+}
+
+// Flush returns the metrics of the flush window ending at now, see
+// Aggregator.
+func (c *Counter) Flush(now time.Time) []Metric {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	window := c.nextWindow(now)
+	if !c.set {
+		return nil
+	}
+	value := c.value
+	c.value, c.set = 0, false
+	return []Metric{c.metric("", MetricTypeCount, window, value)}
+}
+
+// FlushSeries implements Aggregator.
+func (c *Counter) FlushSeries(now time.Time) ([]Metric, []DistributionSeries) {
+	return c.Flush(now), nil
+}
+
+// Gauge records the value of something at a point in time, e.g. a queue
+// length. It is flushed as the last value set during the flush window.
+type Gauge struct {
+	aggregator
+	value float64
+	set   bool
+}
+
+// NewGauge returns a Gauge for the metric name, reported for host, or
+// without a host if it is empty, with tags.
+func NewGauge(name, host string, tags []string) *Gauge {
+	return &Gauge{aggregator: newAggregator(name, host, tags)}
+}
+
+// Set sets the value of the gauge.
+func (g *Gauge) Set(value float64) {
+	g.mu.Lock()
+	g.value = value
+	g.set = true
+	g.mu.Unlock()
+}
+
+// Flush returns the metrics of the flush window ending at now, see
+// Aggregator.
+func (g *Gauge) Flush(now time.Time) []Metric {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	window := g.nextWindow(now)
+	if !g.set {
+		return nil
+	}
+	g.set = false
+	return []Metric{g.metric("", MetricTypeGauge, window, g.value)}
+}
+
+// FlushSeries implements Aggregator.
+func (g *Gauge) FlushSeries(now time.Time) ([]Metric, []DistributionSeries) {
+	return g.Flush(now), nil
+}
+
+// Rate counts occurrences like a Counter, but is flushed as a rate: the
+// occurrences per second during the flush window.
+type Rate struct {
+	aggregator
+	value float64
+	set   bool
+}
+
+// NewRate returns a Rate for the metric name, reported for host, or without
+// a host if it is empty, with tags.
+func NewRate(name, host string, tags []string) *Rate {
+	return &Rate{aggregator: newAggregator(name, host, tags)}
+}
+
+// Add adds delta to the occurrences of the window.
+func (r *Rate) Add(delta float64) {
+	r.mu.Lock()
+	r.value += delta
+	r.set = true
+	r.mu.Unlock()
+}
+
+// Flush returns the metrics of the flush window ending at now, see
+// Aggregator.
+func (r *Rate) Flush(now time.Time) []Metric {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	window := r.nextWindow(now)
+	if !r.set {
+		return nil
+	}
+	value := r.value
+	r.value, r.set = 0, false
+	return []Metric{r.metric("", MetricTypeRate, window, window.rate(value))}
+}
+
+// FlushSeries implements Aggregator.
+func (r *Rate) FlushSeries(now time.Time) ([]Metric, []DistributionSeries) {
+	return r.Flush(now), nil
+}
+
+// Histogram records the distribution of values, e.g. request durations, in
+// this process. It is flushed as the metrics name.avg, name.count,
+// name.max, name.median, name.min and name.<p>percentile for each of its
+// percentiles, e.g. name.95percentile for 0.95.
+type Histogram struct {
+	aggregator
+	percentiles []float64
+	values      []float64
+}
+
+// NewHistogram returns a Histogram for the metric name, reported for host,
+// or without a host if it is empty, with tags. percentiles must be between 0
+// and 1; DefaultHistogramPercentiles are used if there are none.
+func NewHistogram(name, host string, tags []string, percentiles ...float64) (*Histogram, error) {
+	if len(percentiles) == 0 {
+		percentiles = DefaultHistogramPercentiles
+	}
+	for _, p := range percentiles {
+		if !(p >= 0 && p <= 1) {
+			return nil, fmt.Errorf("percentile %v is not between 0 and 1", p)
+		}
+	}
+	return &Histogram{
+		aggregator:  newAggregator(name, host, tags),
+		percentiles: append([]float64(nil), percentiles...),
+	}, nil
+}
+
+// Observe records a value.
+func (h *Histogram) Observe(value float64) {
+	h.mu.Lock()
+	h.values = append(h.values, value)
+	h.mu.Unlock()
+}
+
+// Flush returns the metrics of the flush window ending at now, see
+// Aggregator.
+func (h *Histogram) Flush(now time.Time) []Metric {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	window := h.nextWindow(now)
+	if len(h.values) == 0 {
+		return nil
+	}
+	values := h.values
+	h.values = nil
+	sort.Float64s(values)
+
+	sum := 0.0
+	for _, value := range values {
+		sum += value
+	}
+	metrics := []Metric{
+		h.metric(".avg", MetricTypeGauge, window, sum/float64(len(values))),
+		h.metric(".count", MetricTypeRate, window, window.rate(float64(len(values)))),
+		h.metric(".max", MetricTypeGauge, window, values[len(values)-1]),
+		h.metric(".median", MetricTypeGauge, window, percentile(values, 0.5)),
+		h.metric(".min", MetricTypeGauge, window, values[0]),
+	}
+	for _, p := range h.percentiles {
+		metrics = append(metrics, h.metric("."+percentileName(p), MetricTypeGauge, window, percentile(values, p)))
+	}
+	return metrics
+}
+
+// FlushSeries implements Aggregator.
+func (h *Histogram) FlushSeries(now time.Time) ([]Metric, []DistributionSeries) {
+	return h.Flush(now), nil
+}
+
+// percentile returns the value of the p-th percentile of sorted values, the
+// nearest rank as the agent does.
+func percentile(sorted []float64, p float64) float64 {
+	i := int(p*float64(len(sorted))+0.5) - 1
+	if i < 0 {
+		i = 0
+	}
+	if i >= len(sorted) {
+		i = len(sorted) - 1
+	}
+	return sorted[i]
+}
+
+// percentileName returns the suffix of a percentile metric, e.g.
+// "95percentile" for 0.95 or "99.9percentile" for 0.999.
+func percentileName(p float64) string {
+	return strconv.FormatFloat(float64(int64(p*1000+0.5))/10, 'f', -1, 64) + "percentile"
+}
+
+// Distribution records values like a Histogram, but sends them all with
+// PostDistributionPoints, so that percentiles are computed across every host
+// and process reporting the metric.
+type Distribution struct {
+	aggregator
+	values []float64
+}
+
+// NewDistribution returns a Distribution for the metric name, reported for
+// host, or without a host if it is empty, with tags.
+func NewDistribution(name, host string, tags []string) *Distribution {
+	return &Distribution{aggregator: newAggregator(name, host, tags)}
+}
+
+// Observe records a value.
+func (d *Distribution) Observe(value float64) {
+	d.mu.Lock()
+	d.values = append(d.values, value)
+	d.mu.Unlock()
+}
+
+// Flush returns the values recorded since the previous flush as a point at
+// the start of the flush window, ready for PostDistributionPoints, and starts
+// a new window. It returns nothing if no value was recorded in the window.
+func (d *Distribution) Flush(now time.Time) []DistributionSeries {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	window := d.nextWindow(now)
+	if len(d.values) == 0 {
+		return nil
+	}
+	series := DistributionSeries{
+		Metric: String(d.name),
+		Points: []DistributionPoint{{Timestamp: float64(window.start.Unix()), Values: d.values}},
+		Type:   String(MetricTypeDistribution),
+		Tags:   append([]string(nil), d.tags...),
+	}
+	if d.host != "" {
+		series.Host = String(d.host)
+	}
+	d.values = nil
+	return []DistributionSeries{series}
+}
+
+// FlushSeries implements Aggregator.
+func (d *Distribution) FlushSeries(now time.Time) ([]Metric, []DistributionSeries) {
+	return nil, d.Flush(now)
+}
@@ -0,0 +1,151 @@
+package datadog_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	dd "github.com/zorkian/go-datadog-api"
+)
+
+// metricValues returns the type, timestamp, value and interval of the single
+// point of each metric, by metric name.
+func metricValues(metrics []dd.Metric) map[string][4]interface{} {
+	out := make(map[string][4]interface{})
+	for _, m := range metrics {
+		var interval interface{}
+		if m.Interval != nil {
+			interval = *m.Interval
+		}
+		out[m.GetMetric()] = [4]interface{}{m.GetType(), *m.Points[0][0], *m.Points[0][1], interval}
+	}
+	return out
+}
+
+func TestCounterAndRate(t *testing.T) {
+	counter := dd.NewCounter("jobs.done", "worker-1", []string{"env:prod"})
+	rate := dd.NewRate("jobs.rate", "", nil)
+	start := time.Now()
+	// Start the windows at start, for the rates to be exact.
+	dd.CollectMetrics(start, counter, rate)
+	counter.Inc()
+	counter.Add(4)
+	rate.Add(50)
+
+	metrics, _ := dd.CollectMetrics(start.Add(10*time.Second), counter, rate)
+	assert.Len(t, metrics, 2)
+	assert.Equal(t, "worker-1", metrics[0].GetHost())
+	assert.Equal(t, []string{"env:prod"}, metrics[0].Tags)
+	assert.Nil(t, metrics[1].Host)
+
+	values := metricValues(metrics)
+	assert.Equal(t, "count", values["jobs.done"][0])
+	assert.Equal(t, 5.0, values["jobs.done"][2])
+	assert.Equal(t, "rate", values["jobs.rate"][0])
+	assert.Equal(t, 10, values["jobs.rate"][3])
+	assert.Equal(t, 5.0, values["jobs.rate"][2])
+
+	// Nothing happened in the next window.
+	metrics, _ = dd.CollectMetrics(start.Add(20*time.Second), counter, rate)
+	assert.Empty(t, metrics)
+
+	counter.Inc()
+	metrics = counter.Flush(start.Add(25 * time.Second))
+	assert.Equal(t, float64(start.Add(20*time.Second).Unix()), *metrics[0].Points[0][0])
+	assert.Equal(t, 5, metrics[0].GetInterval())
+}
+
+func TestRatesOverPartialSeconds(t *testing.T) {
+	rate := dd.NewRate("jobs.rate", "", nil)
+	histogram, err := dd.NewHistogram("request.duration", "", nil)
+	assert.Nil(t, err)
+	start := time.Now()
+	dd.CollectMetrics(start, rate, histogram)
+	for i := 0; i < 19; i++ {
+		rate.Add(1)
+		histogram.Observe(1)
+	}
+
+	metrics, _ := dd.CollectMetrics(start.Add(1900*time.Millisecond), rate, histogram)
+	values := metricValues(metrics)
+	assert.InDelta(t, 10.0, values["jobs.rate"][2], 1e-9)
+	assert.Equal(t, 2, values["jobs.rate"][3])
+	assert.InDelta(t, 10.0, values["request.duration.count"][2], 1e-9)
+	assert.Equal(t, 2, values["request.duration.count"][3])
+}
+
+func TestGauge(t *testing.T) {
+	gauge := dd.NewGauge("queue.length", "", nil)
+	gauge.Set(3)
+	gauge.Set(7)
+	metrics := gauge.Flush(time.Now().Add(time.Second))
+	assert.Len(t, metrics, 1)
+	assert.Equal(t, "gauge", metrics[0].GetType())
+	assert.Equal(t, 7.0, *metrics[0].Points[0][1])
+	assert.Nil(t, metrics[0].Interval)
+
+	assert.Empty(t, gauge.Flush(time.Now().Add(2*time.Second)))
+}
+
+func TestHistogram(t *testing.T) {
+	histogram, err := dd.NewHistogram("request.duration", "", nil, 0.5, 0.99, 0.999)
+	assert.Nil(t, err)
+	start := time.Now()
+	histogram.Flush(start)
+	for i := 1; i <= 100; i++ {
+		histogram.Observe(float64(i))
+	}
+
+	values := metricValues(histogram.Flush(start.Add(10 * time.Second)))
+	assert.Len(t, values, 8)
+	assert.Equal(t, 50.5, values["request.duration.avg"][2])
+	assert.Equal(t, "rate", values["request.duration.count"][0])
+	assert.Equal(t, 10.0, values["request.duration.count"][2])
+	assert.Equal(t, 100.0, values["request.duration.max"][2])
+	assert.Equal(t, 1.0, values["request.duration.min"][2])
+	assert.Equal(t, 50.0, values["request.duration.median"][2])
+	assert.Equal(t, 50.0, values["request.duration.50percentile"][2])
+	assert.Equal(t, 99.0, values["request.duration.99percentile"][2])
+	assert.Equal(t, 100.0, values["request.duration.99.9percentile"][2])
+	assert.Equal(t, "gauge", values["request.duration.99percentile"][0])
+
+	assert.Empty(t, histogram.Flush(start.Add(20*time.Second)))
+
+	histogram, err = dd.NewHistogram("default", "", nil)
+	assert.Nil(t, err)
+	histogram.Observe(1)
+	_, ok := metricValues(histogram.Flush(start.Add(time.Second)))["default.95percentile"]
+	assert.True(t, ok)
+
+	for _, p := range []float64{-0.1, 1.5, 95, math.NaN()} {
+		_, err = dd.NewHistogram("invalid", "", nil, 0.5, p)
+		assert.NotNil(t, err, "%v", p)
+	}
+}
+
+func TestDistribution(t *testing.T) {
+	distribution := dd.NewDistribution("payload.size", "host-1", []string{"a:b"})
+	start := time.Now()
+	distribution.Observe(3)
+	distribution.Observe(1)
+
+	series := distribution.Flush(start.Add(time.Second))
+	assert.Len(t, series, 1)
+	assert.Equal(t, "distribution", series[0].GetType())
+	assert.Equal(t, "host-1", series[0].GetHost())
+	assert.Equal(t, []float64{3, 1}, series[0].Points[0].Values)
+
+	assert.Empty(t, distribution.Flush(start.Add(2*time.Second)))
+
+	// Distributions are collected along with the other aggregators.
+	gauge := dd.NewGauge("queue.length", "", nil)
+	gauge.Set(1)
+	distribution.Observe(2)
+	metrics, distributions := dd.CollectMetrics(start.Add(3*time.Second), gauge, distribution)
+	assert.Len(t, metrics, 1)
+	if assert.Len(t, distributions, 1) {
+		assert.Equal(t, "payload.size", distributions[0].GetMetric())
+		assert.Equal(t, []float64{2}, distributions[0].Points[0].Values)
+	}
+}
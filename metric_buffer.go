@@ -0,0 +1,271 @@
+/*
+ * Datadog API for Go
+ *
+ * Please see the included LICENSE file for licensing information.
+ *
+ * Copyright 2020 by authors and contributors.
+ */
+
+package datadog
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Defaults of MetricBufferOpts.
+const (
+	DefaultMetricFlushInterval  = 10 * time.Second
+	DefaultMetricMaxPoints      = 10000
+	DefaultMetricMaxPayloadSize = 3200000
+)
+
+// ErrMetricBufferClosed is returned when adding points to a closed
+// MetricBuffer.
+var ErrMetricBufferClosed = errors.New("metric buffer closed")
+
+// MetricFlushError is returned by the flushes of a MetricBuffer when some of
+// their requests fail. It holds the series of the failed requests, which are
+// no longer buffered, so that they can be added again.
+type MetricFlushError struct {
+	// Err is the error of the first failed request.
+	Err error
+	// Metrics are the series that could not be sent.
+	Metrics []Metric
+}
+
+func (e *MetricFlushError) Error() string {
+	return fmt.Sprintf("failed to send %d metric series: %s", len(e.Metrics), e.Err)
+}
+
+// MetricBufferOpts configures a MetricBuffer. Zero values are replaced by the
+// defaults.
+type MetricBufferOpts struct {
+	// FlushInterval is how often buffered points are sent.
+	FlushInterval time.Duration
+	// MaxPoints is the number of buffered points that triggers a flush
+	// before FlushInterval is over.
+	MaxPoints int
+	// MaxPayloadSize is the size in bytes of the largest request sent. Larger
+	// flushes are split into several requests.
+	MaxPayloadSize int
+	// OnError is called with the error of every flush made in the background,
+	// a *MetricFlushError. When it is nil, errors go to the Logger of the
+	// client, if any, and the points that could not be sent are dropped.
+	OnError func(error)
+}
+
+// MetricBuffer collects metric points and sends them with PostMetrics in the
+// background, in batches. Points of the same series, i.e. with the same
+// metric, host, tags, type, interval and unit, are sent together. A MetricBuffer is safe for
+// concurrent use; Close must be called to send the last points.
+type MetricBuffer struct {
+	client *Client
+	opts   MetricBufferOpts
+
+	// mu protects the buffered series and closed.
+	mu     sync.Mutex
+	series map[string]*Metric
+	// order is the keys of series, in the order they were added.
+	order  []string
+	points int
+	closed bool
+
+	// flushMu makes flushes run one at a time, so that points are sent in
+	// order.
+	flushMu sync.Mutex
+	trigger chan struct{}
+	done    chan struct{}
+	stopped chan struct{}
+}
+
+// NewMetricBuffer returns a MetricBuffer sending its points with client.
+func (client *Client) NewMetricBuffer(opts MetricBufferOpts) *MetricBuffer {
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = DefaultMetricFlushInterval
+	}
+	if opts.MaxPoints <= 0 {
+		opts.MaxPoints = DefaultMetricMaxPoints
+	}
+	if opts.MaxPayloadSize <= 0 {
+		opts.MaxPayloadSize = DefaultMetricMaxPayloadSize
+	}
+	b := &MetricBuffer{
+		client:  client,
+		opts:    opts,
+		series:  make(map[string]*Metric),
+		trigger: make(chan struct{}, 1),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	go b.run()
+	return b
+}
+
+// Add buffers the points of metric. It does not block on the network.
+func (b *MetricBuffer) Add(metric Metric) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return ErrMetricBufferClosed
+	}
+
+	key := metricSeriesKey(&metric)
+	if series, ok := b.series[key]; ok {
+		series.Points = append(series.Points, metric.Points...)
+	} else {
+		metric.Points = append([]DataPoint(nil), metric.Points...)
+		metric.Tags = append([]string(nil), metric.Tags...)
+		b.series[key] = &metric
+		b.order = append(b.order, key)
+	}
+	b.points += len(metric.Points)
+
+	if b.points >= b.opts.MaxPoints {
+		select {
+		case b.trigger <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+// Flush sends the buffered points right away. When requests fail, it returns
+// a *MetricFlushError with their points, which are not buffered anymore.
+func (b *MetricBuffer) Flush() error {
+	b.flushMu.Lock()
+	defer b.flushMu.Unlock()
+
+	b.mu.Lock()
+	series := make([]Metric, 0, len(b.order))
+	for _, key := range b.order {
+		series = append(series, *b.series[key])
+	}
+	b.series = make(map[string]*Metric)
+	b.order = nil
+	b.points = 0
+	b.mu.Unlock()
+
+	var flushErr *MetricFlushError
+	for _, payload := range splitMetricPayloads(series, b.opts.MaxPayloadSize) {
+		if err := b.client.PostMetrics(payload); err != nil {
+			if flushErr == nil {
+				flushErr = &MetricFlushError{Err: err}
+			}
+			flushErr.Metrics = append(flushErr.Metrics, payload...)
+		}
+	}
+	if flushErr != nil {
+		return flushErr
+	}
+	return nil
+}
+
+// Close stops the background flushes and sends the points left. Points
+// added afterwards are refused with ErrMetricBufferClosed.
+func (b *MetricBuffer) Close() error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	b.mu.Unlock()
+
+	close(b.done)
+	<-b.stopped
+	return b.Flush()
+}
+
+// run flushes the buffer every FlushInterval, or when MaxPoints is reached,
+// until Close is called.
+func (b *MetricBuffer) run() {
+	defer close(b.stopped)
+	ticker := time.NewTicker(b.opts.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-b.done:
+			return
+		case <-ticker.C:
+		case <-b.trigger:
+		}
+		if err := b.Flush(); err != nil {
+			b.handleError(err)
+		}
+	}
+}
+
+func (b *MetricBuffer) handleError(err error) {
+	if b.opts.OnError != nil {
+		b.opts.OnError(err)
+	} else if b.client.Logger != nil {
+		b.client.Logger.Error("metric buffer flush failed", "error", err)
+	}
+}
+
+// metricSeriesKey identifies the series of a metric: its name, host, type,
+// interval, unit and tags, in any order.
+func metricSeriesKey(metric *Metric) string {
+	tags := append([]string(nil), metric.Tags...)
+	sort.Strings(tags)
+	interval := ""
+	if metric.HasInterval() {
+		interval = strconv.Itoa(metric.GetInterval())
+	}
+	return strings.Join([]string{metric.GetMetric(), metric.GetHost(), metric.GetType(),
+		interval, metric.GetUnit(), strings.Join(tags, ",")}, "\x00")
+}
+
+// metricPayloadOverhead is the size of the JSON object wrapping the series
+// of a payload: {"series":[]}.
+const metricPayloadOverhead = len(`{"series":[]}`)
+
+// splitMetricPayloads splits series into payloads whose JSON encoding fits
+// in maxSize bytes. A series too large on its own is split by points.
+func splitMetricPayloads(series []Metric, maxSize int) [][]Metric {
+	var payloads [][]Metric
+	var current []Metric
+	size := metricPayloadOverhead
+	for _, metric := range series {
+		for _, part := range splitMetricSeries(metric, maxSize-metricPayloadOverhead) {
+			partSize := metricSize(part) + 1 // The comma separating it.
+			if len(current) > 0 && size+partSize > maxSize {
+				payloads = append(payloads, current)
+				current, size = nil, metricPayloadOverhead
+			}
+			current = append(current, part)
+			size += partSize
+		}
+	}
+	if len(current) > 0 {
+		payloads = append(payloads, current)
+	}
+	return payloads
+}
+
+// splitMetricSeries splits the points of a series in halves until each part
+// fits in maxSize bytes, or holds a single point.
+func splitMetricSeries(metric Metric, maxSize int) []Metric {
+	if len(metric.Points) <= 1 || metricSize(metric) <= maxSize {
+		return []Metric{metric}
+	}
+	half := len(metric.Points) / 2
+	first, second := metric, metric
+	first.Points, second.Points = metric.Points[:half], metric.Points[half:]
+	return append(splitMetricSeries(first, maxSize), splitMetricSeries(second, maxSize)...)
+}
+
+func metricSize(metric Metric) int {
+	data, err := json.Marshal(metric)
+	if err != nil {
+		return 0
+	}
+	return len(data)
+}
@@ -0,0 +1,168 @@
+package datadog_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	dd "github.com/zorkian/go-datadog-api"
+)
+
+// seriesRecorder is a test server recording the series posted to it.
+type seriesRecorder struct {
+	m        sync.Mutex
+	payloads [][]dd.Metric
+	sizes    []int
+	posted   chan struct{}
+}
+
+func newSeriesRecorder(t *testing.T) (*seriesRecorder, *httptest.Server) {
+	rec := &seriesRecorder{posted: make(chan struct{}, 100)}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/series", r.URL.Path)
+		body, _ := ioutil.ReadAll(r.Body)
+		var payload struct {
+			Series []dd.Metric `json:"series"`
+		}
+		assert.Nil(t, json.Unmarshal(body, &payload))
+		rec.m.Lock()
+		rec.payloads = append(rec.payloads, payload.Series)
+		rec.sizes = append(rec.sizes, len(body))
+		rec.m.Unlock()
+		w.Write([]byte(`{"status": "ok"}`))
+		rec.posted <- struct{}{}
+	}))
+	return rec, ts
+}
+
+func (rec *seriesRecorder) get() [][]dd.Metric {
+	rec.m.Lock()
+	defer rec.m.Unlock()
+	return rec.payloads
+}
+
+func testPoint(ts, value float64) dd.DataPoint {
+	return dd.DataPoint{dd.Float64(ts), dd.Float64(value)}
+}
+
+func TestMetricBufferMergesSeries(t *testing.T) {
+	rec, ts := newSeriesRecorder(t)
+	defer ts.Close()
+	client := dd.NewClient("foo", "bar")
+	client.SetBaseUrl(ts.URL)
+
+	buffer := client.NewMetricBuffer(dd.MetricBufferOpts{FlushInterval: time.Hour})
+	assert.Nil(t, buffer.Add(dd.Metric{Metric: dd.String("foo"), Host: dd.String("a"), Tags: []string{"x:1", "y:2"},
+		Points: []dd.DataPoint{testPoint(1, 1)}}))
+	assert.Nil(t, buffer.Add(dd.Metric{Metric: dd.String("bar"), Points: []dd.DataPoint{testPoint(1, 5)}}))
+	// Same series as the first one, with tags in another order.
+	assert.Nil(t, buffer.Add(dd.Metric{Metric: dd.String("foo"), Host: dd.String("a"), Tags: []string{"y:2", "x:1"},
+		Points: []dd.DataPoint{testPoint(2, 2)}}))
+	// Another host makes another series.
+	assert.Nil(t, buffer.Add(dd.Metric{Metric: dd.String("foo"), Host: dd.String("b"), Points: []dd.DataPoint{testPoint(1, 3)}}))
+	// So do another interval and another unit.
+	assert.Nil(t, buffer.Add(dd.Metric{Metric: dd.String("bar"), Interval: dd.Int(10), Points: []dd.DataPoint{testPoint(1, 6)}}))
+	assert.Nil(t, buffer.Add(dd.Metric{Metric: dd.String("bar"), Unit: dd.String("byte"), Points: []dd.DataPoint{testPoint(1, 7)}}))
+
+	assert.Nil(t, buffer.Close())
+	payloads := rec.get()
+	if assert.Len(t, payloads, 1) && assert.Len(t, payloads[0], 5) {
+		assert.Equal(t, "foo", payloads[0][0].GetMetric())
+		assert.Equal(t, []dd.DataPoint{testPoint(1, 1), testPoint(2, 2)}, payloads[0][0].Points)
+		assert.Equal(t, "bar", payloads[0][1].GetMetric())
+		assert.Equal(t, "b", payloads[0][2].GetHost())
+		assert.Equal(t, 10, payloads[0][3].GetInterval())
+		assert.Equal(t, "byte", payloads[0][4].GetUnit())
+	}
+
+	assert.Equal(t, dd.ErrMetricBufferClosed, buffer.Add(dd.Metric{Metric: dd.String("foo")}))
+	assert.Nil(t, buffer.Close())
+}
+
+func TestMetricBufferTriggers(t *testing.T) {
+	rec, ts := newSeriesRecorder(t)
+	defer ts.Close()
+	client := dd.NewClient("foo", "bar")
+	client.SetBaseUrl(ts.URL)
+
+	// Reaching MaxPoints flushes without waiting for the interval.
+	buffer := client.NewMetricBuffer(dd.MetricBufferOpts{FlushInterval: time.Hour, MaxPoints: 2})
+	buffer.Add(dd.Metric{Metric: dd.String("foo"), Points: []dd.DataPoint{testPoint(1, 1)}})
+	buffer.Add(dd.Metric{Metric: dd.String("foo"), Points: []dd.DataPoint{testPoint(2, 1)}})
+	select {
+	case <-rec.posted:
+	case <-time.After(time.Second):
+		t.Fatal("no flush when reaching MaxPoints")
+	}
+	assert.Nil(t, buffer.Close())
+	assert.Len(t, rec.get(), 1)
+
+	// The interval flushes whatever is buffered.
+	buffer = client.NewMetricBuffer(dd.MetricBufferOpts{FlushInterval: 10 * time.Millisecond})
+	defer buffer.Close()
+	buffer.Add(dd.Metric{Metric: dd.String("foo"), Points: []dd.DataPoint{testPoint(3, 1)}})
+	select {
+	case <-rec.posted:
+	case <-time.After(time.Second):
+		t.Fatal("no flush after FlushInterval")
+	}
+}
+
+func TestMetricBufferSplitsPayloads(t *testing.T) {
+	rec, ts := newSeriesRecorder(t)
+	defer ts.Close()
+	client := dd.NewClient("foo", "bar")
+	client.SetBaseUrl(ts.URL)
+
+	buffer := client.NewMetricBuffer(dd.MetricBufferOpts{FlushInterval: time.Hour, MaxPayloadSize: 200})
+	var points []dd.DataPoint
+	for i := 0; i < 20; i++ {
+		points = append(points, testPoint(float64(1600000000+i), float64(i)))
+	}
+	buffer.Add(dd.Metric{Metric: dd.String("foo"), Points: points})
+	buffer.Add(dd.Metric{Metric: dd.String("bar"), Points: points[:1]})
+	assert.Nil(t, buffer.Flush())
+
+	var got []dd.DataPoint
+	for i, payload := range rec.get() {
+		assert.True(t, rec.sizes[i] <= 200, "payload of %d bytes", rec.sizes[i])
+		for _, metric := range payload {
+			if metric.GetMetric() == "foo" {
+				got = append(got, metric.Points...)
+			}
+		}
+	}
+	assert.True(t, len(rec.get()) > 1)
+	assert.Equal(t, points, got)
+	assert.Nil(t, buffer.Close())
+}
+
+func TestMetricBufferFlushError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"errors": ["Forbidden"]}`))
+	}))
+	defer ts.Close()
+	client := dd.NewClient("foo", "bar")
+	client.SetBaseUrl(ts.URL)
+
+	// The points of failed requests are handed back to be added again.
+	buffer := client.NewMetricBuffer(dd.MetricBufferOpts{FlushInterval: time.Hour})
+	metric := dd.Metric{Metric: dd.String("foo"), Points: []dd.DataPoint{testPoint(1, 1)}}
+	assert.Nil(t, buffer.Add(metric))
+	err := buffer.Flush()
+	if flushErr, ok := err.(*dd.MetricFlushError); assert.True(t, ok, "%v", err) {
+		assert.Equal(t, []dd.Metric{metric}, flushErr.Metrics)
+		apiErr, ok := flushErr.Err.(*dd.APIError)
+		assert.True(t, ok)
+		assert.Equal(t, http.StatusForbidden, apiErr.StatusCode)
+	}
+
+	// Nothing is left in the buffer.
+	assert.Nil(t, buffer.Close())
+}
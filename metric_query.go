@@ -0,0 +1,544 @@
+/*
+ * Datadog API for Go
+ *
+ * Please see the included LICENSE file for licensing information.
+ *
+ * Copyright 2020 by authors and contributors.
+ */
+
+package datadog
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// MetricExpr is a node of a metric query, as passed to QueryMetrics or used
+// in graph, widget and SLO queries: a metric, a function of other nodes, a
+// literal, arithmetic between nodes, or a comma-separated list of nodes.
+type MetricExpr interface {
+	String() string
+	metricExpr()
+}
+
+// MetricQuery is a single metric with its aggregation, e.g.
+// "sum:nginx.requests{env:prod} by {host}.rollup(sum, 60)".
+type MetricQuery struct {
+	// Aggregator is the space aggregator, e.g. "avg". It is empty if the
+	// query has none.
+	Aggregator string
+	Metric     string
+	// Scope are the tags the metric is filtered on, all of them if empty.
+	Scope   []string
+	GroupBy []string
+	// Methods are the methods applied to the metric, in order, e.g. rollup
+	// or as_count.
+	Methods []MetricMethod
+}
+
+// MetricMethod is a method applied to a metric, e.g. ".rollup(sum, 60)".
+type MetricMethod struct {
+	Name string
+	Args []string
+}
+
+// MetricFunction is a function applied to expressions, e.g.
+// "per_second(...)" or "anomalies(..., 'basic', 2)".
+type MetricFunction struct {
+	Name string
+	Args []MetricExpr
+}
+
+// MetricLiteral is a number, a quoted string, a keyword or a keyword
+// argument, e.g. the arguments of a function, written as in the query.
+type MetricLiteral string
+
+// MetricBinaryExpr is arithmetic between two expressions with "+", "-", "*"
+// or "/".
+type MetricBinaryExpr struct {
+	Op          string
+	Left, Right MetricExpr
+}
+
+// MetricParenExpr is an expression in parentheses, with the methods applied
+// to it, e.g. "(sum:a{*} + sum:b{*}).rollup(sum, 60)". Parentheses needed for
+// precedence are added when rendering, so this is only used to keep the ones
+// of a parsed query, or to apply methods to an expression.
+type MetricParenExpr struct {
+	Expr    MetricExpr
+	Methods []MetricMethod
+}
+
+// MetricExprList is a comma-separated list of expressions, e.g. the queries
+// of several series requested at once with QueryMetrics.
+type MetricExprList []MetricExpr
+
+func (MetricQuery) metricExpr()      {}
+func (MetricFunction) metricExpr()   {}
+func (MetricLiteral) metricExpr()    {}
+func (MetricBinaryExpr) metricExpr() {}
+func (MetricParenExpr) metricExpr()  {}
+func (MetricExprList) metricExpr()   {}
+
+func (e MetricLiteral) String() string { return string(e) }
+
+func (e MetricParenExpr) String() string {
+	return "(" + e.Expr.String() + ")" + metricMethodsString(e.Methods)
+}
+
+func (e MetricExprList) String() string {
+	exprs := make([]string, len(e))
+	for i, expr := range e {
+		exprs[i] = expr.String()
+	}
+	return strings.Join(exprs, ", ")
+}
+
+func (q MetricQuery) String() string {
+	var s string
+	if q.Aggregator != "" {
+		s = q.Aggregator + ":"
+	}
+	scope := "*"
+	if len(q.Scope) > 0 {
+		scope = strings.Join(q.Scope, ",")
+	}
+	s += q.Metric + "{" + scope + "}"
+	if len(q.GroupBy) > 0 {
+		s += " by {" + strings.Join(q.GroupBy, ",") + "}"
+	}
+	return s + metricMethodsString(q.Methods)
+}
+
+func metricMethodsString(methods []MetricMethod) string {
+	var s string
+	for _, method := range methods {
+		s += "." + method.Name + "(" + strings.Join(method.Args, ", ") + ")"
+	}
+	return s
+}
+
+func (e MetricFunction) String() string {
+	args := make([]string, len(e.Args))
+	for i, arg := range e.Args {
+		args[i] = arg.String()
+	}
+	return e.Name + "(" + strings.Join(args, ", ") + ")"
+}
+
+func (e MetricBinaryExpr) String() string {
+	left, right := e.Left.String(), e.Right.String()
+	// Operands binding looser than the operator need parentheses, and so
+	// does a right operand of the same precedence as operators associate to
+	// the left.
+	if l, ok := e.Left.(MetricBinaryExpr); ok && metricPrecedence(l.Op) < metricPrecedence(e.Op) {
+		left = "(" + left + ")"
+	}
+	if r, ok := e.Right.(MetricBinaryExpr); ok && metricPrecedence(r.Op) <= metricPrecedence(e.Op) {
+		right = "(" + right + ")"
+	}
+	return left + " " + e.Op + " " + right
+}
+
+func metricPrecedence(op string) int {
+	if op == "*" || op == "/" {
+		return 2
+	}
+	return 1
+}
+
+// NewMetricQuery returns the query of metric aggregated with aggregator,
+// e.g. "avg", filtered on the scope tags.
+func NewMetricQuery(aggregator, metric string, scope ...string) MetricQuery {
+	return MetricQuery{Aggregator: aggregator, Metric: metric, Scope: scope}
+}
+
+// By returns the query grouped by tags.
+func (q MetricQuery) By(tags ...string) MetricQuery {
+	q.GroupBy = append(append([]string(nil), q.GroupBy...), tags...)
+	return q
+}
+
+// WithMethod returns the query with the method name applied to it.
+func (q MetricQuery) WithMethod(name string, args ...string) MetricQuery {
+	q.Methods = append(append([]MetricMethod(nil), q.Methods...), MetricMethod{Name: name, Args: args})
+	return q
+}
+
+// Rollup returns the query with its points aggregated with aggregator over
+// intervals of seconds.
+func (q MetricQuery) Rollup(aggregator string, seconds int) MetricQuery {
+	return q.WithMethod("rollup", aggregator, strconv.Itoa(seconds))
+}
+
+// AsCount returns the query with its values reported as counts.
+func (q MetricQuery) AsCount() MetricQuery {
+	return q.WithMethod("as_count")
+}
+
+// AsRate returns the query with its values reported as rates per second.
+func (q MetricQuery) AsRate() MetricQuery {
+	return q.WithMethod("as_rate")
+}
+
+// Method returns the first method of the query with the given name.
+func (q MetricQuery) Method(name string) (MetricMethod, bool) {
+	for _, method := range q.Methods {
+		if method.Name == name {
+			return method, true
+		}
+	}
+	return MetricMethod{}, false
+}
+
+// MetricFunc applies the function name to args.
+func MetricFunc(name string, args ...MetricExpr) MetricFunction {
+	return MetricFunction{Name: name, Args: args}
+}
+
+// PerSecond returns the rate per second of expr.
+func PerSecond(expr MetricExpr) MetricFunction {
+	return MetricFunc("per_second", expr)
+}
+
+// Anomalies returns the anomaly detection of expr with the given algorithm,
+// e.g. "basic" or "agile", and bounds.
+func Anomalies(expr MetricExpr, algorithm string, bounds float64) MetricFunction {
+	return MetricFunc("anomalies", expr, MetricString(algorithm), MetricNumber(bounds))
+}
+
+// Timeshift returns expr shifted by seconds, e.g. -3600 for an hour before.
+func Timeshift(expr MetricExpr, seconds int) MetricFunction {
+	return MetricFunc("timeshift", expr, MetricNumber(float64(seconds)))
+}
+
+// MetricNumber returns the literal of a number.
+func MetricNumber(value float64) MetricLiteral {
+	return MetricLiteral(strconv.FormatFloat(value, 'f', -1, 64))
+}
+
+// MetricString returns the literal of a string, between single quotes.
+func MetricString(value string) MetricLiteral {
+	return MetricLiteral("'" + value + "'")
+}
+
+// MetricAdd returns the sum of expressions.
+func MetricAdd(exprs ...MetricExpr) MetricExpr {
+	return metricChain("+", exprs)
+}
+
+// MetricSub returns left minus right.
+func MetricSub(left, right MetricExpr) MetricExpr {
+	return MetricBinaryExpr{Op: "-", Left: left, Right: right}
+}
+
+// MetricMul returns the product of expressions.
+func MetricMul(exprs ...MetricExpr) MetricExpr {
+	return metricChain("*", exprs)
+}
+
+// MetricDiv returns left divided by right.
+func MetricDiv(left, right MetricExpr) MetricExpr {
+	return MetricBinaryExpr{Op: "/", Left: left, Right: right}
+}
+
+func metricChain(op string, exprs []MetricExpr) MetricExpr {
+	if len(exprs) == 0 {
+		return nil
+	}
+	expr := exprs[0]
+	for _, right := range exprs[1:] {
+		expr = MetricBinaryExpr{Op: op, Left: expr, Right: right}
+	}
+	return expr
+}
+
+// MetricQueries returns the metric queries of expr, in the order they
+// appear.
+func MetricQueries(expr MetricExpr) []MetricQuery {
+	var queries []MetricQuery
+	RewriteMetricQueries(expr, func(q MetricQuery) MetricQuery {
+		queries = append(queries, q)
+		return q
+	})
+	return queries
+}
+
+// RewriteMetricQueries returns expr with every metric query replaced by the
+// one returned by fn, e.g. to add a tag to the scope of all of them. expr
+// itself is left as it is.
+func RewriteMetricQueries(expr MetricExpr, fn func(MetricQuery) MetricQuery) MetricExpr {
+	switch e := expr.(type) {
+	case MetricQuery:
+		return fn(e)
+	case MetricFunction:
+		args := make([]MetricExpr, len(e.Args))
+		for i, arg := range e.Args {
+			args[i] = RewriteMetricQueries(arg, fn)
+		}
+		return MetricFunction{Name: e.Name, Args: args}
+	case MetricBinaryExpr:
+		return MetricBinaryExpr{Op: e.Op, Left: RewriteMetricQueries(e.Left, fn), Right: RewriteMetricQueries(e.Right, fn)}
+	case MetricParenExpr:
+		return MetricParenExpr{Expr: RewriteMetricQueries(e.Expr, fn), Methods: e.Methods}
+	case MetricExprList:
+		exprs := make(MetricExprList, len(e))
+		for i, expr := range e {
+			exprs[i] = RewriteMetricQueries(expr, fn)
+		}
+		return exprs
+	}
+	return expr
+}
+
+// ParseMetricQuery parses a metric query, e.g.
+// "per_second(sum:nginx.requests{env:prod} by {host}) / 2". A query of
+// several comma-separated expressions is parsed as a MetricExprList. Parsing
+// a query and rendering it back with String gives the same query, apart from
+// the spacing around operators and arguments.
+func ParseMetricQuery(query string) (MetricExpr, error) {
+	p := &metricQueryParser{query: query}
+	expr, err := p.parseList()
+	if err == nil && p.peek() != 0 {
+		err = fmt.Errorf("unexpected %q", p.query[p.pos])
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid metric query %q: %s at offset %d", query, err, p.pos)
+	}
+	return expr, nil
+}
+
+var (
+	metricNumberRegex = regexp.MustCompile(`^-?(?:[0-9]+\.?[0-9]*|\.[0-9]+)(?:[eE][-+]?[0-9]+)?`)
+	metricNameRegex   = regexp.MustCompile(`^[A-Za-z_][\w.]*`)
+	metricByRegex     = regexp.MustCompile(`^\s*by\s*\{`)
+)
+
+// metricQueryParser is a recursive descent parser of metric queries, where
+// "*" and "/" bind tighter than "+" and "-".
+type metricQueryParser struct {
+	query string
+	pos   int
+}
+
+// peek skips spaces and returns the next character, or 0 at the end of the
+// query.
+func (p *metricQueryParser) peek() byte {
+	for p.pos < len(p.query) && p.query[p.pos] == ' ' {
+		p.pos++
+	}
+	if p.pos == len(p.query) {
+		return 0
+	}
+	return p.query[p.pos]
+}
+
+// expect consumes the character c, after spaces.
+func (p *metricQueryParser) expect(c byte) error {
+	switch p.peek() {
+	case c:
+		p.pos++
+		return nil
+	case 0:
+		return fmt.Errorf("expected %q, got end of query", c)
+	}
+	return fmt.Errorf("expected %q, got %q", c, p.query[p.pos])
+}
+
+// until consumes and returns everything up to the character c, and c.
+func (p *metricQueryParser) until(c byte) (string, error) {
+	end := strings.IndexByte(p.query[p.pos:], c)
+	if end < 0 {
+		return "", fmt.Errorf("missing %q", c)
+	}
+	s := p.query[p.pos : p.pos+end]
+	p.pos += end + 1
+	return s, nil
+}
+
+// parseList parses comma-separated expressions, returning the expression
+// itself if there is only one.
+func (p *metricQueryParser) parseList() (MetricExpr, error) {
+	expr, err := p.parseSum()
+	if err != nil || p.peek() != ',' {
+		return expr, err
+	}
+	list := MetricExprList{expr}
+	for p.peek() == ',' {
+		p.pos++
+		expr, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, expr)
+	}
+	return list, nil
+}
+
+func (p *metricQueryParser) parseSum() (MetricExpr, error) {
+	return p.parseBinary("+-", p.parseProduct)
+}
+
+func (p *metricQueryParser) parseProduct() (MetricExpr, error) {
+	return p.parseBinary("*/", p.parseOperand)
+}
+
+func (p *metricQueryParser) parseBinary(ops string, operand func() (MetricExpr, error)) (MetricExpr, error) {
+	left, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	for c := p.peek(); c != 0 && strings.IndexByte(ops, c) >= 0; c = p.peek() {
+		p.pos++
+		right, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		left = MetricBinaryExpr{Op: string(c), Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *metricQueryParser) parseOperand() (MetricExpr, error) {
+	c := p.peek()
+	switch {
+	case c == 0:
+		return nil, fmt.Errorf("unexpected end of query")
+	case c == '(':
+		p.pos++
+		expr, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(')'); err != nil {
+			return nil, err
+		}
+		methods, err := p.parseMethods()
+		if err != nil {
+			return nil, err
+		}
+		return MetricParenExpr{Expr: expr, Methods: methods}, nil
+	case c == '\'' || c == '"':
+		start := p.pos
+		p.pos++
+		if _, err := p.until(c); err != nil {
+			return nil, err
+		}
+		return MetricLiteral(p.query[start:p.pos]), nil
+	}
+
+	rest := p.query[p.pos:]
+	if number := metricNumberRegex.FindString(rest); number != "" {
+		p.pos += len(number)
+		return MetricLiteral(number), nil
+	}
+	name := metricNameRegex.FindString(rest)
+	if name == "" {
+		return nil, fmt.Errorf("unexpected %q", c)
+	}
+	p.pos += len(name)
+
+	switch p.peek() {
+	case '(':
+		p.pos++
+		return p.parseFunction(name)
+	case ':':
+		p.pos++
+		metric := metricNameRegex.FindString(p.query[p.pos:])
+		if metric == "" {
+			return nil, fmt.Errorf("missing metric name")
+		}
+		p.pos += len(metric)
+		return p.parseMetric(name, metric)
+	case '{':
+		return p.parseMetric("", name)
+	case '=':
+		// A keyword argument of a function, e.g. interval='60m'.
+		p.pos++
+		value, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		return MetricLiteral(name + "=" + value.String()), nil
+	}
+	return MetricLiteral(name), nil
+}
+
+// parseFunction parses the arguments of the function name, after its
+// opening parenthesis.
+func (p *metricQueryParser) parseFunction(name string) (MetricExpr, error) {
+	function := MetricFunction{Name: name}
+	if p.peek() == ')' {
+		p.pos++
+		return function, nil
+	}
+	for {
+		arg, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		function.Args = append(function.Args, arg)
+		if p.peek() != ',' {
+			break
+		}
+		p.pos++
+	}
+	if err := p.expect(')'); err != nil {
+		return nil, err
+	}
+	return function, nil
+}
+
+// parseMetric parses the scope, grouping and methods following a metric
+// name.
+func (p *metricQueryParser) parseMetric(aggregator, metric string) (MetricExpr, error) {
+	query := MetricQuery{Aggregator: aggregator, Metric: metric}
+	if err := p.expect('{'); err != nil {
+		return nil, err
+	}
+	scope, err := p.until('}')
+	if err != nil {
+		return nil, err
+	}
+	query.Scope = splitQueryList(strings.TrimSpace(scope), "*")
+
+	if by := metricByRegex.FindString(p.query[p.pos:]); by != "" {
+		p.pos += len(by)
+		groups, err := p.until('}')
+		if err != nil {
+			return nil, err
+		}
+		query.GroupBy = splitQueryList(strings.TrimSpace(groups), "")
+	}
+
+	query.Methods, err = p.parseMethods()
+	if err != nil {
+		return nil, err
+	}
+	return query, nil
+}
+
+// parseMethods parses the methods applied to the metric or the expression in
+// parentheses just parsed, if any.
+func (p *metricQueryParser) parseMethods() ([]MetricMethod, error) {
+	var methods []MetricMethod
+	for p.pos < len(p.query) && p.query[p.pos] == '.' {
+		p.pos++
+		name := metricNameRegex.FindString(p.query[p.pos:])
+		if name == "" {
+			return nil, fmt.Errorf("missing method name")
+		}
+		p.pos += len(name)
+		if err := p.expect('('); err != nil {
+			return nil, err
+		}
+		args, err := p.until(')')
+		if err != nil {
+			return nil, err
+		}
+		methods = append(methods, MetricMethod{Name: name, Args: splitQueryList(strings.TrimSpace(args), "")})
+	}
+	return methods, nil
+}
@@ -0,0 +1,146 @@
+package datadog_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	dd "github.com/zorkian/go-datadog-api"
+)
+
+func TestParseMetricQueryRoundTrip(t *testing.T) {
+	queries := []string{
+		"avg:system.cpu.user{*}",
+		"system.load.1{host:foo}",
+		"sum:nginx.requests{env:prod,service:web} by {host,region}",
+		"sum:nginx.requests{env:prod}.rollup(sum, 60).as_count()",
+		"per_second(sum:nginx.requests{*} by {host})",
+		"anomalies(avg:system.cpu.user{env:prod}, 'basic', 2)",
+		"timeshift(avg:system.cpu.user{*}, -3600)",
+		"top(avg:system.cpu.user{*} by {host}, 10, 'mean', 'desc')",
+		"sum:errors{*}.as_count() / sum:requests{*}.as_count() * 100",
+		"(sum:a{*} + sum:b{*}) / 2",
+		"sum:a{*} - (sum:b{*} - sum:c{*})",
+		"week_before(avg:system.load.1{!host:foo,role:db*})",
+		"forecast(avg:system.load.1{*}, 'linear', 1, interval='60m')",
+		"1.5e3 * avg:x{*}",
+		"count_nonzero()",
+		"avg:a{*} by {host}, avg:b{*} by {host}",
+		"(avg:a{*} + avg:b{*}).rollup(sum, 60)",
+		"(avg:a{*} + avg:b{*}).rollup(sum, 60).as_count() / 2, per_second(sum:c{*})",
+	}
+	for _, query := range queries {
+		expr, err := dd.ParseMetricQuery(query)
+		if assert.Nil(t, err, query) {
+			assert.Equal(t, query, expr.String())
+		}
+	}
+}
+
+func TestParseMetricQueryNormalizesSpacing(t *testing.T) {
+	expr, err := dd.ParseMetricQuery("sum:a{ env:prod , host:b } by{host}.rollup(sum,60)/sum:b{*}")
+	assert.Nil(t, err)
+	assert.Equal(t, "sum:a{env:prod,host:b} by {host}.rollup(sum, 60) / sum:b{*}", expr.String())
+}
+
+func TestParseMetricQueryStructure(t *testing.T) {
+	expr, err := dd.ParseMetricQuery("per_second(sum:nginx.requests{env:prod} by {host}.rollup(sum, 60)) / 2")
+	assert.Nil(t, err)
+
+	div, ok := expr.(dd.MetricBinaryExpr)
+	assert.True(t, ok)
+	assert.Equal(t, "/", div.Op)
+	assert.Equal(t, dd.MetricLiteral("2"), div.Right)
+
+	queries := dd.MetricQueries(expr)
+	assert.Equal(t, []dd.MetricQuery{{
+		Aggregator: "sum",
+		Metric:     "nginx.requests",
+		Scope:      []string{"env:prod"},
+		GroupBy:    []string{"host"},
+		Methods:    []dd.MetricMethod{{Name: "rollup", Args: []string{"sum", "60"}}},
+	}}, queries)
+	rollup, ok := queries[0].Method("rollup")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"sum", "60"}, rollup.Args)
+	_, ok = queries[0].Method("fill")
+	assert.False(t, ok)
+}
+
+func TestParseMetricQueryList(t *testing.T) {
+	expr, err := dd.ParseMetricQuery("avg:a{*} by {host},(avg:b{*} - avg:c{*}).rollup(max, 300)")
+	assert.Nil(t, err)
+
+	list, ok := expr.(dd.MetricExprList)
+	if assert.True(t, ok) && assert.Len(t, list, 2) {
+		assert.Equal(t, dd.NewMetricQuery("avg", "a").By("host"), list[0])
+		paren, ok := list[1].(dd.MetricParenExpr)
+		assert.True(t, ok)
+		assert.Equal(t, []dd.MetricMethod{{Name: "rollup", Args: []string{"max", "300"}}}, paren.Methods)
+	}
+	assert.Equal(t, "avg:a{*} by {host}, (avg:b{*} - avg:c{*}).rollup(max, 300)", expr.String())
+	assert.Len(t, dd.MetricQueries(expr), 3)
+}
+
+func TestParseMetricQueryErrors(t *testing.T) {
+	for _, query := range []string{
+		"",
+		"avg:system.cpu.user",
+		"avg:{*}",
+		"avg:system.cpu.user{*",
+		"sum:a{*} +",
+		"per_second(sum:a{*}",
+		"(sum:a{*}",
+		"sum:a{*} by {host",
+		"sum:a{*}.rollup(sum, 60",
+		"sum:a{*} )",
+		"'unterminated",
+		"sum:a{*},",
+		", sum:a{*}",
+		"(sum:a{*}).rollup(sum, 60",
+	} {
+		_, err := dd.ParseMetricQuery(query)
+		assert.NotNil(t, err, query)
+	}
+}
+
+func TestMetricQueryBuilder(t *testing.T) {
+	errors := dd.NewMetricQuery("sum", "errors", "env:prod").By("host").AsCount()
+	requests := dd.NewMetricQuery("sum", "requests", "env:prod").By("host").AsCount()
+	ratio := dd.MetricMul(dd.MetricDiv(errors, requests), dd.MetricNumber(100))
+	assert.Equal(t, "sum:errors{env:prod} by {host}.as_count() / sum:requests{env:prod} by {host}.as_count() * 100", ratio.String())
+
+	assert.Equal(t, "(sum:a{*} + sum:b{*}) * 2",
+		dd.MetricMul(dd.MetricAdd(dd.NewMetricQuery("sum", "a"), dd.NewMetricQuery("sum", "b")), dd.MetricNumber(2)).String())
+	assert.Equal(t, "sum:a{*} / (sum:b{*} * 2)",
+		dd.MetricDiv(dd.NewMetricQuery("sum", "a"), dd.MetricMul(dd.NewMetricQuery("sum", "b"), dd.MetricNumber(2))).String())
+
+	cpu := dd.NewMetricQuery("avg", "system.cpu.user").Rollup("max", 300)
+	assert.Equal(t, "anomalies(avg:system.cpu.user{*}.rollup(max, 300), 'agile', 3)", dd.Anomalies(cpu, "agile", 3).String())
+	assert.Equal(t, "timeshift(per_second(avg:system.cpu.user{*}.rollup(max, 300)), -86400)",
+		dd.Timeshift(dd.PerSecond(cpu), -86400).String())
+
+	// Building on a query leaves it as it is.
+	assert.Equal(t, "avg:system.cpu.user{*}.rollup(max, 300)", cpu.String())
+	assert.Equal(t, "avg:system.cpu.user{*}.rollup(max, 300).as_rate()", cpu.AsRate().String())
+}
+
+func TestRewriteMetricQueries(t *testing.T) {
+	expr, err := dd.ParseMetricQuery("(sum:a{env:prod} + sum:b{*}) / timeshift(sum:a{env:prod}, -3600)")
+	assert.Nil(t, err)
+
+	rewritten := dd.RewriteMetricQueries(expr, func(q dd.MetricQuery) dd.MetricQuery {
+		q.Scope = append(append([]string(nil), q.Scope...), "team:x")
+		return q
+	})
+	assert.Equal(t, "(sum:a{env:prod,team:x} + sum:b{team:x}) / timeshift(sum:a{env:prod,team:x}, -3600)", rewritten.String())
+	assert.Equal(t, "(sum:a{env:prod} + sum:b{*}) / timeshift(sum:a{env:prod}, -3600)", expr.String())
+	assert.Len(t, dd.MetricQueries(expr), 3)
+
+	expr, err = dd.ParseMetricQuery("(sum:a{*} + sum:b{*}).rollup(sum, 60), sum:c{*}")
+	assert.Nil(t, err)
+	rewritten = dd.RewriteMetricQueries(expr, func(q dd.MetricQuery) dd.MetricQuery {
+		q.Scope = []string{"team:x"}
+		return q
+	})
+	assert.Equal(t, "(sum:a{team:x} + sum:b{team:x}).rollup(sum, 60), sum:c{team:x}", rewritten.String())
+}
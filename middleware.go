@@ -0,0 +1,39 @@
+/*
+ * Datadog API for Go
+ *
+ * Please see the included LICENSE file for licensing information.
+ *
+ * Copyright 2020 by authors and contributors.
+ */
+
+package datadog
+
+import (
+	"net/http"
+)
+
+// RoundTripFunc sends a single HTTP request to the Datadog API and returns its
+// response.
+type RoundTripFunc func(req *http.Request) (*http.Response, error)
+
+// Middleware wraps the sending of requests, e.g. to trace, log, measure or
+// sign them. It must call next to send the request on, and may change the
+// request before and inspect the response after.
+type Middleware func(next RoundTripFunc) RoundTripFunc
+
+// Use adds middlewares to the client. They see every attempt at sending a
+// request, retries included, the first middleware added being the outermost
+// one. Use must not be called concurrently with requests.
+func (client *Client) Use(middlewares ...Middleware) {
+	client.middlewares = append(client.middlewares, middlewares...)
+}
+
+// roundTrip sends req through the middlewares of the client, then the HTTP
+// client.
+func (client *Client) roundTrip(req *http.Request) (*http.Response, error) {
+	next := RoundTripFunc(client.HttpClient.Do)
+	for i := len(client.middlewares) - 1; i >= 0; i-- {
+		next = client.middlewares[i](next)
+	}
+	return next(req)
+}
@@ -0,0 +1,88 @@
+package datadog
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddlewares(t *testing.T) {
+	record := func(calls *[]string, name string) Middleware {
+		return func(next RoundTripFunc) RoundTripFunc {
+			return func(req *http.Request) (*http.Response, error) {
+				*calls = append(*calls, name+" "+req.Method+" "+req.URL.Path)
+				resp, err := next(req)
+				if err == nil {
+					*calls = append(*calls, name+" "+resp.Status)
+				}
+				return resp, err
+			}
+		}
+	}
+
+	t.Run("Middlewares wrap requests in order", func(t *testing.T) {
+		s := makeTestServer(200, `{"status": "ok"}`)
+		defer s.Close()
+		c := NewClient("sample_api_key", "sample_app_key")
+		c.SetBaseUrl(s.URL)
+
+		var calls []string
+		c.Use(record(&calls, "outer"), record(&calls, "inner"))
+		err := c.doJsonRequest("GET", "/v1/something", nil, nil)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{
+			"outer GET /api/v1/something",
+			"inner GET /api/v1/something",
+			"inner 200 OK",
+			"outer 200 OK",
+		}, calls)
+	})
+	t.Run("Middlewares can change requests", func(t *testing.T) {
+		var signature string
+		mux := http.NewServeMux()
+		mux.HandleFunc("/api/v1/something", func(w http.ResponseWriter, r *http.Request) {
+			signature = r.Header.Get("X-Signature")
+		})
+		s := httptest.NewServer(mux)
+		defer s.Close()
+		c := NewClient("sample_api_key", "sample_app_key")
+		c.SetBaseUrl(s.URL)
+
+		c.Use(func(next RoundTripFunc) RoundTripFunc {
+			return func(req *http.Request) (*http.Response, error) {
+				req.Header.Set("X-Signature", "signed")
+				return next(req)
+			}
+		})
+		err := c.doJsonRequest("POST", "/v1/something", nil, nil)
+		assert.Nil(t, err)
+		assert.Equal(t, "signed", signature)
+	})
+	t.Run("Middlewares see every retry", func(t *testing.T) {
+		s := makeTestServer(502, "")
+		defer s.Close()
+		c := NewClient("sample_api_key", "sample_app_key")
+		c.SetBaseUrl(s.URL)
+		c.RetryPolicy = &DefaultRetryPolicy{InitialInterval: 10 * time.Millisecond}
+		c.RetryTimeout = 100 * time.Millisecond
+
+		var calls []string
+		c.Use(record(&calls, "mw"))
+		err := c.doJsonRequest("GET", "/v1/something", nil, nil)
+		assert.NotNil(t, err)
+		assert.True(t, len(calls) > 2)
+	})
+	t.Run("Middlewares added to a view are not added to the client", func(t *testing.T) {
+		c := NewClient("sample_api_key", "sample_app_key")
+		var calls []string
+		c.Use(record(&calls, "client"))
+		view := c.WithContext(context.Background())
+		view.Use(record(&calls, "view"))
+		assert.Len(t, c.middlewares, 1)
+		assert.Len(t, view.middlewares, 2)
+	})
+}
@@ -12,6 +12,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"reflect"
 	"strconv"
 	"strings"
 )
@@ -58,6 +59,20 @@ type ThresholdWindows struct {
 	TriggerWindow  *string `json:"trigger_window,omitempty"`
 }
 
+// SchedulingOptions configures when a monitor evaluates its query.
+type SchedulingOptions struct {
+	EvaluationWindow *EvaluationWindow `json:"evaluation_window,omitempty"`
+}
+
+// EvaluationWindow makes a monitor evaluate its query over a cumulative
+// window starting at a fixed time of the day, hour or month, instead of a
+// rolling window.
+type EvaluationWindow struct {
+	DayStarts   *string `json:"day_starts,omitempty"`
+	HourStarts  *int    `json:"hour_starts,omitempty"`
+	MonthStarts *int    `json:"month_starts,omitempty"`
+}
+
 type NoDataTimeframe int
 
 func (tf *NoDataTimeframe) UnmarshalJSON(data []byte) error {
@@ -91,6 +106,50 @@ type Options struct {
 	Locked            *bool             `json:"locked,omitempty"`
 	EnableLogsSample  *bool             `json:"enable_logs_sample,omitempty"`
 	QueryConfig       *QueryConfig      `json:"queryConfig,omitempty"`
+
+	// NotifyBy lists the tags notifications are grouped by, e.g. "cluster",
+	// or "*" for a single notification for all groups.
+	NotifyBy []string `json:"notify_by,omitempty"`
+	// RenotifyStatuses lists the states renotified every RenotifyInterval:
+	// "alert", "warn" and "no data".
+	RenotifyStatuses    []string `json:"renotify_statuses,omitempty"`
+	RenotifyOccurrences *int     `json:"renotify_occurrences,omitempty"`
+	// NewGroupDelay is the number of seconds new groups are ignored for.
+	NewGroupDelay        *int               `json:"new_group_delay,omitempty"`
+	GroupbySimpleMonitor *bool              `json:"groupby_simple_monitor,omitempty"`
+	SchedulingOptions    *SchedulingOptions `json:"scheduling_options,omitempty"`
+	// OnMissingData is what to do without data: "default", "show_no_data",
+	// "show_and_notify_no_data" or "resolve". It replaces NotifyNoData for
+	// log, event and APM monitors.
+	OnMissingData *string `json:"on_missing_data,omitempty"`
+
+	// unknown holds the options this library does not know, so that they
+	// are sent back unchanged when the monitor is updated.
+	unknown map[string]json.RawMessage
+}
+
+// UnmarshalJSON decodes options, keeping the ones this library does not know.
+func (o *Options) UnmarshalJSON(data []byte) error {
+	type options Options
+	if err := json.Unmarshal(data, (*options)(o)); err != nil {
+		return err
+	}
+	unknown, err := unknownFields(data, reflect.TypeOf(*o))
+	if err != nil {
+		return err
+	}
+	o.unknown = unknown
+	return nil
+}
+
+// MarshalJSON encodes options, including the unknown ones that were decoded.
+func (o Options) MarshalJSON() ([]byte, error) {
+	type options Options
+	data, err := json.Marshal(options(o))
+	if err != nil {
+		return nil, err
+	}
+	return addUnknownFields(data, o.unknown)
 }
 
 type TriggeringValue struct {
@@ -105,7 +164,7 @@ type GroupData struct {
 	LastResolvedTs  *int             `json:"last_resolved_ts,omitempty"`
 	LastTriggeredTs *int             `json:"last_triggered_ts,omitempty"`
 	Name            *string          `json:"name,omitempty"`
-	Status          *string          `json:"status,omitempty"`
+	Status          *MonitorStatus   `json:"status,omitempty"`
 	TriggeringValue *TriggeringValue `json:"triggering_value,omitempty"`
 }
 
@@ -116,17 +175,43 @@ type State struct {
 // Monitor allows watching a metric or check that you care about,
 // notifying your team when some defined threshold is exceeded
 type Monitor struct {
-	Creator              *Creator `json:"creator,omitempty"`
-	Id                   *int     `json:"id,omitempty"`
-	Type                 *string  `json:"type,omitempty"`
-	Query                *string  `json:"query,omitempty"`
-	Name                 *string  `json:"name,omitempty"`
-	Message              *string  `json:"message,omitempty"`
-	OverallState         *string  `json:"overall_state,omitempty"`
-	OverallStateModified *string  `json:"overall_state_modified,omitempty"`
-	Tags                 []string `json:"tags"`
-	Options              *Options `json:"options,omitempty"`
-	State                State    `json:"state,omitempty"`
+	Creator              *Creator       `json:"creator,omitempty"`
+	Id                   *int           `json:"id,omitempty"`
+	Type                 *string        `json:"type,omitempty"`
+	Query                *string        `json:"query,omitempty"`
+	Name                 *string        `json:"name,omitempty"`
+	Message              *string        `json:"message,omitempty"`
+	OverallState         *MonitorStatus `json:"overall_state,omitempty"`
+	OverallStateModified *string        `json:"overall_state_modified,omitempty"`
+	Tags                 []string       `json:"tags"`
+	Options              *Options       `json:"options,omitempty"`
+	State                State          `json:"state,omitempty"`
+	// Priority ranges from 1, the highest, to 5.
+	Priority *int `json:"priority,omitempty"`
+	// RestrictedRoles lists the UUIDs of the only roles allowed to edit the
+	// monitor.
+	RestrictedRoles []string `json:"restricted_roles,omitempty"`
+
+	// unknown holds the fields this library does not know, in lossless mode.
+	unknown map[string]json.RawMessage
+}
+
+func (m *Monitor) setUnknownFields(unknown map[string]json.RawMessage) {
+	m.unknown = unknown
+}
+
+func (m *Monitor) readOnlyFields() []string {
+	return []string{"created", "created_at", "modified", "deleted", "org_id", "creator", "overall_state", "overall_state_modified", "state", "matching_downtimes"}
+}
+
+// MarshalJSON encodes the monitor, including the fields kept in lossless mode.
+func (m Monitor) MarshalJSON() ([]byte, error) {
+	type monitor Monitor
+	data, err := json.Marshal(monitor(m))
+	if err != nil {
+		return nil, err
+	}
+	return addUnknownFields(data, m.unknown)
 }
 
 // Creator contains the creator of the monitor
@@ -202,7 +287,8 @@ func (client *Client) DeleteMonitor(id int) error {
 		nil, nil)
 }
 
-// ForceDeleteMonitor removes a monitor from the system, even if it's linked to SLOs or group monitors
+// ForceDeleteMonitor removes a monitor from the system, even if it's linked to SLOs or group monitors.
+// Use ForceDeleteMonitorWithOptions to check what depends on it first.
 func (client *Client) ForceDeleteMonitor(id int) error {
 	return client.doJsonRequest("DELETE", fmt.Sprintf("/v1/monitor/%d?force=true", id),
 		nil, nil)
@@ -221,6 +307,10 @@ type MonitorQueryOpts struct {
 	Tags          []string
 	MonitorTags   []string
 	WithDowntimes *bool
+	// Page and PageSize restrict the results to one page of monitors, the
+	// first page being 0. All monitors are returned when Page is not set.
+	Page     *int
+	PageSize *int
 }
 
 // GetMonitorsWithOptions returns a slice of all monitors
@@ -251,6 +341,14 @@ func (client *Client) GetMonitorsWithOptions(opts MonitorQueryOpts) ([]Monitor,
 		query = append(query, fmt.Sprintf("name=%s", v))
 	}
 
+	if v, ok := opts.GetPageOk(); ok {
+		query = append(query, fmt.Sprintf("page=%d", v))
+	}
+
+	if v, ok := opts.GetPageSizeOk(); ok {
+		query = append(query, fmt.Sprintf("page_size=%d", v))
+	}
+
 	queryString, err := url.ParseQuery(strings.Join(query, "&"))
 	if err != nil {
 		return nil, err
@@ -262,6 +360,194 @@ func (client *Client) GetMonitorsWithOptions(opts MonitorQueryOpts) ([]Monitor,
 	return out.Monitors, nil
 }
 
+// MonitorPager iterates over the monitors matching a MonitorQueryOpts one page
+// at a time, so that large sets of monitors are not fetched in a single call:
+//
+//	pager := client.NewMonitorPager(datadog.MonitorQueryOpts{Tags: []string{"env:prod"}}, 100)
+//	for pager.Next() {
+//		for _, monitor := range pager.Monitors() {
+//			...
+//		}
+//	}
+//	if err := pager.Err(); err != nil {
+//		...
+//	}
+type MonitorPager struct {
+	client   *Client
+	opts     MonitorQueryOpts
+	page     int
+	pageSize int
+	monitors []Monitor
+	done     bool
+	err      error
+}
+
+// DefaultMonitorPageSize is the page size of a MonitorPager created with a
+// page size that is not positive.
+const DefaultMonitorPageSize = 100
+
+// NewMonitorPager returns a pager over the monitors matching opts, pageSize at
+// a time, or DefaultMonitorPageSize if pageSize is not positive. The Page and
+// PageSize of opts are ignored.
+func (client *Client) NewMonitorPager(opts MonitorQueryOpts, pageSize int) *MonitorPager {
+	if pageSize <= 0 {
+		pageSize = DefaultMonitorPageSize
+	}
+	return &MonitorPager{
+		client:   client,
+		opts:     opts,
+		pageSize: pageSize,
+	}
+}
+
+// Next fetches the next page of monitors. It returns false once there are no
+// more monitors or a request failed, see Err.
+func (p *MonitorPager) Next() bool {
+	if p.done {
+		return false
+	}
+
+	opts := p.opts
+	opts.Page = Int(p.page)
+	opts.PageSize = Int(p.pageSize)
+	p.monitors, p.err = p.client.GetMonitorsWithOptions(opts)
+	if p.err != nil || len(p.monitors) == 0 {
+		p.done = true
+		p.monitors = nil
+		return false
+	}
+
+	// A partial page is the last one.
+	p.done = len(p.monitors) < p.pageSize
+	p.page++
+	return true
+}
+
+// Monitors returns the page of monitors fetched by the last call to Next.
+func (p *MonitorPager) Monitors() []Monitor {
+	return p.monitors
+}
+
+// Err returns the error that stopped the pager, if any.
+func (p *MonitorPager) Err() error {
+	return p.err
+}
+
+// MonitorSearchOpts contains the options supported by
+// https://docs.datadoghq.com/api/v1/monitors/#monitors-search
+// The Name, Tags and MonitorTags filters of MonitorQueryOpts are turned into
+// search terms, GroupStates is sent as the group_states parameter, and Page
+// and PageSize select a page of results.
+type MonitorSearchOpts struct {
+	MonitorQueryOpts
+	// Query holds extra search terms, e.g. "type:metric muted:false".
+	Query *string
+	// Sort orders the results, e.g. "name,asc".
+	Sort *string
+}
+
+// MonitorSearchResult is the response of the monitor search endpoint.
+type MonitorSearchResult struct {
+	Counts   *MonitorSearchCounts         `json:"counts,omitempty"`
+	Metadata *MonitorSearchMetadata       `json:"metadata,omitempty"`
+	Monitors []MonitorSearchResultMonitor `json:"monitors"`
+}
+
+// MonitorSearchCounts holds the number of monitors matching a search, by facet.
+type MonitorSearchCounts struct {
+	Status []MonitorSearchCount      `json:"status"`
+	Type   []MonitorSearchCount      `json:"type"`
+	Tag    []MonitorSearchCount      `json:"tag"`
+	Muted  []MonitorSearchMutedCount `json:"muted"`
+}
+
+// MonitorSearchCount is the number of monitors with a given facet value.
+type MonitorSearchCount struct {
+	Name  *string `json:"name,omitempty"`
+	Count *int    `json:"count,omitempty"`
+}
+
+// MonitorSearchMutedCount is the number of monitors muted, or not.
+type MonitorSearchMutedCount struct {
+	Name  *bool `json:"name,omitempty"`
+	Count *int  `json:"count,omitempty"`
+}
+
+// MonitorSearchMetadata describes the page of results of a search.
+type MonitorSearchMetadata struct {
+	TotalCount *int `json:"total_count,omitempty"`
+	PageCount  *int `json:"page_count,omitempty"`
+	Page       *int `json:"page,omitempty"`
+	PerPage    *int `json:"per_page,omitempty"`
+}
+
+// MonitorSearchResultMonitor is the summary of a monitor returned by a search.
+type MonitorSearchResultMonitor struct {
+	Id              *int                        `json:"id,omitempty"`
+	Name            *string                     `json:"name,omitempty"`
+	Type            *string                     `json:"type,omitempty"`
+	Classification  *string                     `json:"classification,omitempty"`
+	Status          *string                     `json:"status,omitempty"`
+	Query           *string                     `json:"query,omitempty"`
+	Tags            []string                    `json:"tags,omitempty"`
+	Scopes          []string                    `json:"scopes,omitempty"`
+	Metrics         []string                    `json:"metrics,omitempty"`
+	Notifications   []MonitorSearchNotification `json:"notifications,omitempty"`
+	LastTriggeredTs *int                        `json:"last_triggered_ts,omitempty"`
+	Creator         *Creator                    `json:"creator,omitempty"`
+}
+
+// MonitorSearchNotification is a notification target of a monitor.
+type MonitorSearchNotification struct {
+	Handle *string `json:"handle,omitempty"`
+	Name   *string `json:"name,omitempty"`
+}
+
+// searchTerms turns the filters of opts into monitor search terms.
+func (opts MonitorSearchOpts) searchTerms() string {
+	var terms []string
+	if v, ok := opts.GetNameOk(); ok {
+		terms = append(terms, strconv.Quote(v))
+	}
+	for _, tag := range opts.Tags {
+		terms = append(terms, "scope:"+strconv.Quote(tag))
+	}
+	for _, tag := range opts.MonitorTags {
+		terms = append(terms, "tag:"+strconv.Quote(tag))
+	}
+	if v, ok := opts.GetQueryOk(); ok {
+		terms = append(terms, v)
+	}
+	return strings.Join(terms, " ")
+}
+
+// SearchMonitors searches monitors, and counts the matching ones by status,
+// type, tag and muted state.
+func (client *Client) SearchMonitors(opts MonitorSearchOpts) (*MonitorSearchResult, error) {
+	var out MonitorSearchResult
+	query := url.Values{}
+	if terms := opts.searchTerms(); terms != "" {
+		query.Set("query", terms)
+	}
+	if len(opts.GroupStates) > 0 {
+		query.Set("group_states", strings.Join(opts.GroupStates, ","))
+	}
+	if v, ok := opts.GetPageOk(); ok {
+		query.Set("page", strconv.Itoa(v))
+	}
+	if v, ok := opts.GetPageSizeOk(); ok {
+		query.Set("per_page", strconv.Itoa(v))
+	}
+	if v, ok := opts.GetSortOk(); ok {
+		query.Set("sort", v)
+	}
+
+	if err := client.doJsonRequest("GET", "/v1/monitor/search?"+query.Encode(), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
 // MuteMonitors turns off monitoring notifications
 func (client *Client) MuteMonitors() error {
 	return client.doJsonRequest("POST", "/v1/monitor/mute_all", nil, nil)
@@ -0,0 +1,147 @@
+/*
+ * Datadog API for Go
+ *
+ * Please see the included LICENSE file for licensing information.
+ *
+ * Copyright 2020 by authors and contributors.
+ */
+
+package datadog
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// bulkConcurrency is the number of monitors muted or unmuted at the same time
+// by the bulk operations.
+const bulkConcurrency = 8
+
+// BulkMuteReport is the outcome of BulkMuteMonitors. It records the state of
+// every muted monitor before the mute, for BulkUnmuteMonitors to restore it.
+type BulkMuteReport struct {
+	Scope string
+	End   time.Time
+	// Muted lists the monitors that were muted, in the order they were
+	// listed.
+	Muted []MutedMonitor
+	// Errors holds the error of every monitor that could not be muted, by
+	// monitor ID.
+	Errors map[int]error
+}
+
+// MutedMonitor is a monitor muted by BulkMuteMonitors.
+type MutedMonitor struct {
+	Id int
+	// PreviousSilenced is the Options.Silenced of the monitor before it was
+	// muted.
+	PreviousSilenced map[string]int
+}
+
+// BulkUnmuteReport is the outcome of BulkUnmuteMonitors.
+type BulkUnmuteReport struct {
+	// Restored lists the IDs of the monitors restored to their previous
+	// state.
+	Restored []int
+	// Errors holds the error of every monitor that could not be restored, by
+	// monitor ID.
+	Errors map[int]error
+}
+
+// BulkMuteMonitors mutes every monitor matching filter on scope, e.g.
+// "host:foo", or on all scopes if it is empty. The mutes expire at end, or
+// never if it is the zero time. Monitors are muted a few at a time; the ones
+// that fail are listed in the Errors of the report, which only fails as a
+// whole if the monitors cannot be listed.
+func (client *Client) BulkMuteMonitors(filter MonitorQueryOpts, scope string, end time.Time) (*BulkMuteReport, error) {
+	monitors, err := client.GetMonitorsWithOptions(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	mute := &MuteMonitorScope{}
+	if scope != "" {
+		mute.SetScope(scope)
+	}
+	if !end.IsZero() {
+		mute.SetEnd(int(end.Unix()))
+	}
+
+	results := make([]error, len(monitors))
+	forEachConcurrently(len(monitors), func(i int) {
+		results[i] = client.MuteMonitorScope(monitors[i].GetId(), mute)
+	})
+
+	report := &BulkMuteReport{Scope: scope, End: end, Errors: make(map[int]error)}
+	for i, monitor := range monitors {
+		if results[i] != nil {
+			report.Errors[monitor.GetId()] = results[i]
+			continue
+		}
+		previous := make(map[string]int, len(monitor.GetOptions().Silenced))
+		for scope, end := range monitor.GetOptions().Silenced {
+			previous[scope] = end
+		}
+		report.Muted = append(report.Muted, MutedMonitor{Id: monitor.GetId(), PreviousSilenced: previous})
+	}
+	return report, nil
+}
+
+// BulkUnmuteMonitors restores the monitors muted by BulkMuteMonitors to the
+// silenced state they had before, including the mutes they already had. Only
+// the silenced option of the monitors is updated.
+func (client *Client) BulkUnmuteMonitors(report *BulkMuteReport) *BulkUnmuteReport {
+	results := make([]error, len(report.Muted))
+	forEachConcurrently(len(report.Muted), func(i int) {
+		results[i] = client.restoreSilenced(report.Muted[i])
+	})
+
+	out := &BulkUnmuteReport{Errors: make(map[int]error)}
+	for i, muted := range report.Muted {
+		if results[i] != nil {
+			out.Errors[muted.Id] = results[i]
+		} else {
+			out.Restored = append(out.Restored, muted.Id)
+		}
+	}
+	return out
+}
+
+// restoreSilenced sets the Options.Silenced of a monitor back to the one
+// recorded by BulkMuteMonitors. Only silenced is sent, so that changes made
+// to the other options of the monitor since it was muted are kept.
+func (client *Client) restoreSilenced(muted MutedMonitor) error {
+	if len(muted.PreviousSilenced) == 0 {
+		// An empty Silenced is left out of the options, so it cannot be
+		// restored with an update.
+		return client.UnmuteMonitorScopes(muted.Id, &UnmuteMonitorScopes{AllScopes: Bool(true)})
+	}
+
+	type silencedOptions struct {
+		Silenced map[string]int `json:"silenced"`
+	}
+	update := struct {
+		Options silencedOptions `json:"options"`
+	}{silencedOptions{muted.PreviousSilenced}}
+	return client.doJsonRequest("PUT", fmt.Sprintf("/v1/monitor/%d", muted.Id), update, nil)
+}
+
+// forEachConcurrently calls fn for every index up to n, running at most
+// bulkConcurrency calls at the same time, and returns once all are done.
+func forEachConcurrently(n int, fn func(i int)) {
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, bulkConcurrency)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
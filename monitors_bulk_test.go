@@ -0,0 +1,65 @@
+package datadog_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	dd "github.com/zorkian/go-datadog-api"
+)
+
+func TestBulkMuteMonitors(t *testing.T) {
+	var m sync.Mutex
+	requests := make(map[string]string)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		m.Lock()
+		requests[r.Method+" "+r.URL.Path] = string(body)
+		m.Unlock()
+
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/v1/monitor":
+			assert.Equal(t, "monitor_tags=team%3Aweb", r.URL.RawQuery)
+			w.Write([]byte(`[
+				{"id": 1, "options": {"silenced": {}}},
+				{"id": 2, "options": {"silenced": {"role:db": 1600000000}}},
+				{"id": 3, "options": {}}
+			]`))
+		case "POST /api/v1/monitor/3/mute":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer ts.Close()
+
+	client := dd.NewClient("foo", "bar")
+	client.SetBaseUrl(ts.URL)
+
+	end := time.Unix(1700000000, 0)
+	report, err := client.BulkMuteMonitors(dd.MonitorQueryOpts{MonitorTags: []string{"team:web"}}, "host:foo", end)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []dd.MutedMonitor{
+		{Id: 1, PreviousSilenced: map[string]int{}},
+		{Id: 2, PreviousSilenced: map[string]int{"role:db": 1600000000}},
+	}, report.Muted)
+	if assert.Len(t, report.Errors, 1) {
+		assert.NotNil(t, report.Errors[3])
+	}
+	assert.JSONEq(t, `{"scope": "host:foo", "end": 1700000000}`, requests["POST /api/v1/monitor/1/mute"])
+	assert.JSONEq(t, `{"scope": "host:foo", "end": 1700000000}`, requests["POST /api/v1/monitor/2/mute"])
+
+	unmute := client.BulkUnmuteMonitors(report)
+	assert.Equal(t, []int{1, 2}, unmute.Restored)
+	assert.Empty(t, unmute.Errors)
+	assert.JSONEq(t, `{"all_scopes": true}`, requests["POST /api/v1/monitor/1/unmute"])
+	// Only silenced is sent, so that other changes to the options are kept.
+	assert.JSONEq(t, `{"options": {"silenced": {"role:db": 1600000000}}}`, requests["PUT /api/v1/monitor/2"])
+	assert.NotContains(t, requests, "GET /api/v1/monitor/2")
+}
@@ -0,0 +1,188 @@
+/*
+ * Datadog API for Go
+ *
+ * Please see the included LICENSE file for licensing information.
+ *
+ * Copyright 2020 by authors and contributors.
+ */
+
+package datadog
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MonitorDependencies is the graph of the composite monitors and SLOs built
+// on top of other monitors, used to find out what deleting a monitor would
+// break.
+type MonitorDependencies struct {
+	// composites maps a monitor ID to the IDs of the composite monitors
+	// referring to it in their query.
+	composites map[int][]int
+	// slos maps a monitor ID to the monitor based SLOs it is part of.
+	slos map[int][]*ServiceLevelObjective
+}
+
+// MonitorDependents are the composite monitors and SLOs depending on a
+// monitor.
+type MonitorDependents struct {
+	// Composites are the IDs of the composite monitors referring to the
+	// monitor, directly or through other composites.
+	Composites []int
+	// SLOs are the SLOs based on the monitor or on one of Composites.
+	SLOs []*ServiceLevelObjective
+}
+
+// Empty returns true if nothing depends on the monitor.
+func (d *MonitorDependents) Empty() bool {
+	return len(d.Composites) == 0 && len(d.SLOs) == 0
+}
+
+// NewMonitorDependencies builds the dependency graph of monitors and SLOs.
+// It returns an error if the query of a composite monitor cannot be parsed,
+// as its dependencies would be missed.
+func NewMonitorDependencies(monitors []Monitor, slos []*ServiceLevelObjective) (*MonitorDependencies, error) {
+	deps := &MonitorDependencies{
+		composites: make(map[int][]int),
+		slos:       make(map[int][]*ServiceLevelObjective),
+	}
+	for _, monitor := range monitors {
+		if monitor.GetType() != "composite" {
+			continue
+		}
+		query, err := ParseCompositeQuery(monitor.GetQuery())
+		if err != nil {
+			return nil, fmt.Errorf("composite monitor %d: %s", monitor.GetId(), err)
+		}
+		for _, id := range query.MonitorIDs() {
+			deps.composites[id] = append(deps.composites[id], monitor.GetId())
+		}
+	}
+	for _, slo := range slos {
+		for _, id := range slo.MonitorIDs {
+			deps.slos[id] = append(deps.slos[id], slo)
+		}
+	}
+	return deps, nil
+}
+
+// Dependents returns the composite monitors and SLOs that would break if the
+// monitor with the given ID was deleted.
+func (d *MonitorDependencies) Dependents(id int) *MonitorDependents {
+	dependents := &MonitorDependents{}
+
+	// Walk up the composites, as a composite built on a broken composite is
+	// broken too.
+	seen := map[int]bool{id: true}
+	queue := []int{id}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, composite := range d.composites[current] {
+			if !seen[composite] {
+				seen[composite] = true
+				dependents.Composites = append(dependents.Composites, composite)
+				queue = append(queue, composite)
+			}
+		}
+	}
+	sort.Ints(dependents.Composites)
+
+	seenSLOs := make(map[*ServiceLevelObjective]bool)
+	for _, monitorID := range append([]int{id}, dependents.Composites...) {
+		for _, slo := range d.slos[monitorID] {
+			if !seenSLOs[slo] {
+				seenSLOs[slo] = true
+				dependents.SLOs = append(dependents.SLOs, slo)
+			}
+		}
+	}
+	return dependents
+}
+
+// MonitorInUseError is returned instead of deleting a monitor that composite
+// monitors or SLOs depend on.
+type MonitorInUseError struct {
+	ID         int
+	Dependents *MonitorDependents
+}
+
+func (e *MonitorInUseError) Error() string {
+	var parts []string
+	if len(e.Dependents.Composites) > 0 {
+		ids := make([]string, len(e.Dependents.Composites))
+		for i, id := range e.Dependents.Composites {
+			ids[i] = fmt.Sprintf("%d", id)
+		}
+		parts = append(parts, "composite monitors "+strings.Join(ids, ", "))
+	}
+	if len(e.Dependents.SLOs) > 0 {
+		ids := make([]string, len(e.Dependents.SLOs))
+		for i, slo := range e.Dependents.SLOs {
+			ids[i] = slo.GetID()
+		}
+		parts = append(parts, "SLOs "+strings.Join(ids, ", "))
+	}
+	return fmt.Sprintf("monitor %d is used by %s", e.ID, strings.Join(parts, " and "))
+}
+
+// sloPageSize is the number of SLOs fetched per request when listing all of
+// them.
+const sloPageSize = 1000
+
+// GetMonitorDependencies fetches all monitors and SLOs and builds their
+// dependency graph.
+func (client *Client) GetMonitorDependencies() (*MonitorDependencies, error) {
+	monitors, err := client.GetMonitors()
+	if err != nil {
+		return nil, err
+	}
+
+	var slos []*ServiceLevelObjective
+	for offset := 0; ; offset += sloPageSize {
+		page, err := client.SearchServiceLevelObjectives(sloPageSize, offset, "", nil)
+		if err != nil {
+			return nil, err
+		}
+		slos = append(slos, page...)
+		if len(page) < sloPageSize {
+			break
+		}
+	}
+
+	return NewMonitorDependencies(monitors, slos)
+}
+
+// GetMonitorDependents returns the composite monitors and SLOs that would
+// break if the monitor with the given ID was deleted.
+func (client *Client) GetMonitorDependents(id int) (*MonitorDependents, error) {
+	deps, err := client.GetMonitorDependencies()
+	if err != nil {
+		return nil, err
+	}
+	return deps.Dependents(id), nil
+}
+
+// ForceDeleteMonitorOpts contains the options of ForceDeleteMonitorWithOptions.
+type ForceDeleteMonitorOpts struct {
+	// CheckDependents makes the delete fail with a *MonitorInUseError if
+	// composite monitors or SLOs depend on the monitor.
+	CheckDependents bool
+}
+
+// ForceDeleteMonitorWithOptions removes a monitor from the system like
+// ForceDeleteMonitor, optionally checking first that nothing depends on it.
+func (client *Client) ForceDeleteMonitorWithOptions(id int, opts ForceDeleteMonitorOpts) error {
+	if opts.CheckDependents {
+		dependents, err := client.GetMonitorDependents(id)
+		if err != nil {
+			return err
+		}
+		if !dependents.Empty() {
+			return &MonitorInUseError{ID: id, Dependents: dependents}
+		}
+	}
+	return client.ForceDeleteMonitor(id)
+}
@@ -0,0 +1,90 @@
+package datadog_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	dd "github.com/zorkian/go-datadog-api"
+)
+
+func testDependencyMonitors() []dd.Monitor {
+	return []dd.Monitor{
+		{Id: dd.Int(1), Type: dd.String("metric alert"), Query: dd.String("avg(last_5m):sum:foo{*} > 1")},
+		{Id: dd.Int(2), Type: dd.String("metric alert"), Query: dd.String("avg(last_5m):sum:bar{*} > 1")},
+		{Id: dd.Int(3), Type: dd.String("composite"), Query: dd.String("1 && 2")},
+		{Id: dd.Int(4), Type: dd.String("composite"), Query: dd.String("3 || !1")},
+		{Id: dd.Int(5), Type: dd.String("composite"), Query: dd.String("2 || 2")},
+	}
+}
+
+func TestMonitorDependencies(t *testing.T) {
+	sloA := &dd.ServiceLevelObjective{ID: dd.String("a"), MonitorIDs: []int{1}}
+	sloB := &dd.ServiceLevelObjective{ID: dd.String("b"), MonitorIDs: []int{4, 1}}
+	deps, err := dd.NewMonitorDependencies(testDependencyMonitors(), []*dd.ServiceLevelObjective{sloA, sloB})
+	assert.Nil(t, err)
+
+	assert.Equal(t, &dd.MonitorDependents{
+		Composites: []int{3, 4},
+		SLOs:       []*dd.ServiceLevelObjective{sloA, sloB},
+	}, deps.Dependents(1))
+	assert.Equal(t, &dd.MonitorDependents{
+		Composites: []int{3, 4, 5},
+		SLOs:       []*dd.ServiceLevelObjective{sloB},
+	}, deps.Dependents(2))
+	assert.True(t, deps.Dependents(5).Empty())
+
+	err = &dd.MonitorInUseError{ID: 1, Dependents: deps.Dependents(1)}
+	assert.Equal(t, "monitor 1 is used by composite monitors 3, 4 and SLOs a, b", err.Error())
+
+	_, err = dd.NewMonitorDependencies([]dd.Monitor{
+		{Id: dd.Int(6), Type: dd.String("composite"), Query: dd.String("1 &&")},
+	}, nil)
+	assert.NotNil(t, err)
+
+	// Composite queries may be written over several lines.
+	monitors := append(testDependencyMonitors(),
+		dd.Monitor{Id: dd.Int(6), Type: dd.String("composite"), Query: dd.String("(\n\t1 &&\n\t2\n)\r\n|| 5")})
+	deps, err = dd.NewMonitorDependencies(monitors, nil)
+	if assert.Nil(t, err) {
+		assert.Equal(t, []int{3, 4, 6}, deps.Dependents(1).Composites)
+		assert.Equal(t, []int{6}, deps.Dependents(5).Composites)
+	}
+}
+
+func TestForceDeleteMonitorWithOptions(t *testing.T) {
+	var deleted []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/api/v1/monitor":
+			w.Write([]byte(`[
+				{"id": 1, "type": "metric alert", "query": "avg(last_5m):sum:foo{*} > 1"},
+				{"id": 2, "type": "metric alert", "query": "avg(last_5m):sum:bar{*} > 1"},
+				{"id": 3, "type": "composite", "query": "1 && 2"}
+			]`))
+		case r.Method == "GET" && r.URL.Path == "/api/v1/slo":
+			w.Write([]byte(`{"data": [{"id": "abc", "type": "monitor", "monitor_ids": [2]}]}`))
+		case r.Method == "DELETE":
+			deleted = append(deleted, r.URL.Path+"?"+r.URL.RawQuery)
+			w.Write([]byte(`{}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+	}))
+	defer ts.Close()
+
+	client := dd.NewClient("foo", "bar")
+	client.SetBaseUrl(ts.URL)
+	opts := dd.ForceDeleteMonitorOpts{CheckDependents: true}
+
+	err := client.ForceDeleteMonitorWithOptions(1, opts)
+	if assert.IsType(t, &dd.MonitorInUseError{}, err) {
+		assert.Equal(t, []int{3}, err.(*dd.MonitorInUseError).Dependents.Composites)
+	}
+	assert.Nil(t, deleted)
+
+	assert.Nil(t, client.ForceDeleteMonitorWithOptions(3, opts))
+	assert.Nil(t, client.ForceDeleteMonitorWithOptions(1, dd.ForceDeleteMonitorOpts{}))
+	assert.Equal(t, []string{"/api/v1/monitor/3?force=true", "/api/v1/monitor/1?force=true"}, deleted)
+}
@@ -0,0 +1,472 @@
+/*
+ * Datadog API for Go
+ *
+ * Please see the included LICENSE file for licensing information.
+ *
+ * Copyright 2020 by authors and contributors.
+ */
+
+package datadog
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// MessageTemplate is a parsed monitor message, such as Monitor.Message or
+// Options.EscalationMessage, written in the Datadog template syntax:
+//
+//	{{#is_alert}}CPU is at {{value}} on {{host.name}} @slack-ops{{/is_alert}}
+type MessageTemplate struct {
+	Nodes []MessageNode
+}
+
+// MessageNode is a piece of a message template: MessageText,
+// MessageVariable, MessageRawVariable or MessageSection.
+type MessageNode interface {
+	messageNode()
+}
+
+// MessageText is text copied as it is.
+type MessageText string
+
+// MessageVariable is a template variable, e.g. {{value}} or {{host.name}}.
+type MessageVariable string
+
+// MessageRawVariable is a template variable between triple braces, e.g.
+// {{{host.name}}}, which Datadog renders without escaping HTML.
+type MessageRawVariable string
+
+// MessageSection is a conditional block, e.g. {{#is_alert}}...{{/is_alert}},
+// or {{^is_alert}}...{{/is_alert}} when Inverted. Args are the arguments of
+// conditions such as {{#is_match "host.name" "web"}}. Else holds the nodes
+// after an {{else}} of the section, rendered when Nodes are not; it is nil if
+// the section has no {{else}}.
+type MessageSection struct {
+	Name     string
+	Args     []string
+	Inverted bool
+	Nodes    []MessageNode
+	Else     []MessageNode
+}
+
+func (MessageText) messageNode()        {}
+func (MessageVariable) messageNode()    {}
+func (MessageRawVariable) messageNode() {}
+func (MessageSection) messageNode()     {}
+
+// messageConditions maps the conditions of sections to the number of
+// arguments they take.
+var messageConditions = map[string]int{
+	"is_alert":            0,
+	"is_alert_recovery":   0,
+	"is_alert_to_warning": 0,
+	"is_no_data":          0,
+	"is_no_data_recovery": 0,
+	"is_recovery":         0,
+	"is_renotify":         0,
+	"is_warning":          0,
+	"is_warning_recovery": 0,
+	"is_priority":         1,
+	// is_match and is_exact_match take a variable, then one or more strings.
+	"is_match":       -2,
+	"is_exact_match": -2,
+}
+
+var (
+	messageVariableRegex = regexp.MustCompile(`^[A-Za-z_][\w-]*(\.[\w-]+)*$`)
+	messageArgsRegex     = regexp.MustCompile(`"([^"]*)"|'([^']*)'|(\S+)`)
+)
+
+// ParseMessageTemplate parses and validates a monitor message. It reports
+// unbalanced braces, sections that are not closed or closed in the wrong
+// order, misplaced {{else}}, unknown conditions and malformed variables.
+func ParseMessageTemplate(message string) (*MessageTemplate, error) {
+	type open struct {
+		section MessageSection
+		offset  int
+		parent  []MessageNode
+		// inElse is true once the {{else}} of the section is passed.
+		inElse bool
+	}
+	var stack []open
+	var nodes []MessageNode
+
+	rest, offset := message, 0
+	for rest != "" {
+		start := strings.Index(rest, "{{")
+		if start < 0 {
+			nodes = append(nodes, MessageText(rest))
+			break
+		}
+		if start > 0 {
+			nodes = append(nodes, MessageText(rest[:start]))
+		}
+		if strings.HasPrefix(rest[start:], "{{{") {
+			end := strings.Index(rest[start:], "}}}")
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed {{{ at offset %d", offset+start)
+			}
+			tag := strings.TrimSpace(rest[start+3 : start+end])
+			if !messageVariableRegex.MatchString(tag) {
+				return nil, fmt.Errorf("invalid variable {{{%s}}} at offset %d", tag, offset+start)
+			}
+			nodes = append(nodes, MessageRawVariable(tag))
+			rest = rest[start+end+3:]
+			offset += start + end + 3
+			continue
+		}
+		end := strings.Index(rest[start:], "}}")
+		if end < 0 {
+			return nil, fmt.Errorf("unclosed {{ at offset %d", offset+start)
+		}
+		tag := strings.TrimSpace(rest[start+2 : start+end])
+		tagOffset := offset + start
+
+		switch {
+		case tag == "else":
+			if len(stack) == 0 {
+				return nil, fmt.Errorf("{{else}} outside of a section at offset %d", tagOffset)
+			}
+			top := &stack[len(stack)-1]
+			if top.inElse {
+				return nil, fmt.Errorf("second {{else}} of {{#%s}} at offset %d", top.section.Name, tagOffset)
+			}
+			top.section.Nodes = nodes
+			top.inElse = true
+			nodes = []MessageNode{}
+		case strings.HasPrefix(tag, "#"), strings.HasPrefix(tag, "^"):
+			fields := messageArgs(tag[1:])
+			if len(fields) == 0 {
+				return nil, fmt.Errorf("section without a condition at offset %d", tagOffset)
+			}
+			section := MessageSection{Name: fields[0], Args: fields[1:], Inverted: tag[0] == '^'}
+			if err := checkMessageCondition(section); err != nil {
+				return nil, fmt.Errorf("%s at offset %d", err, tagOffset)
+			}
+			stack = append(stack, open{section: section, offset: tagOffset, parent: nodes})
+			nodes = nil
+		case strings.HasPrefix(tag, "/"):
+			name := strings.TrimSpace(tag[1:])
+			if len(stack) == 0 {
+				return nil, fmt.Errorf("{{/%s}} closes no section at offset %d", name, tagOffset)
+			}
+			top := stack[len(stack)-1]
+			if top.section.Name != name {
+				return nil, fmt.Errorf("{{/%s}} at offset %d does not close {{#%s}}", name, tagOffset, top.section.Name)
+			}
+			stack = stack[:len(stack)-1]
+			if top.inElse {
+				top.section.Else = nodes
+			} else {
+				top.section.Nodes = nodes
+			}
+			nodes = append(top.parent, top.section)
+		default:
+			if !messageVariableRegex.MatchString(tag) {
+				return nil, fmt.Errorf("invalid variable {{%s}} at offset %d", tag, tagOffset)
+			}
+			nodes = append(nodes, MessageVariable(tag))
+		}
+
+		rest = rest[start+end+2:]
+		offset = tagOffset + end + 2
+	}
+
+	if len(stack) > 0 {
+		top := stack[len(stack)-1]
+		return nil, fmt.Errorf("{{#%s}} at offset %d is not closed", top.section.Name, top.offset)
+	}
+	return &MessageTemplate{Nodes: nodes}, nil
+}
+
+// messageArgs splits the content of a section tag, unquoting its arguments.
+func messageArgs(tag string) []string {
+	var args []string
+	for _, match := range messageArgsRegex.FindAllStringSubmatch(tag, -1) {
+		args = append(args, match[1]+match[2]+match[3])
+	}
+	return args
+}
+
+func checkMessageCondition(section MessageSection) error {
+	want, ok := messageConditions[section.Name]
+	switch {
+	case !ok:
+		return fmt.Errorf("unknown condition %q", section.Name)
+	case want >= 0 && len(section.Args) != want:
+		return fmt.Errorf("condition %q takes %d arguments, not %d", section.Name, want, len(section.Args))
+	case want < 0 && len(section.Args) < -want:
+		return fmt.Errorf("condition %q takes at least %d arguments", section.Name, -want)
+	}
+	return nil
+}
+
+// String renders the template back to its source, normalizing the spacing
+// inside tags.
+func (t *MessageTemplate) String() string {
+	var b bytes.Buffer
+	writeMessageNodes(&b, t.Nodes)
+	return b.String()
+}
+
+func writeMessageNodes(b *bytes.Buffer, nodes []MessageNode) {
+	for _, node := range nodes {
+		switch n := node.(type) {
+		case MessageText:
+			b.WriteString(string(n))
+		case MessageVariable:
+			b.WriteString("{{" + string(n) + "}}")
+		case MessageRawVariable:
+			b.WriteString("{{{" + string(n) + "}}}")
+		case MessageSection:
+			prefix := "#"
+			if n.Inverted {
+				prefix = "^"
+			}
+			b.WriteString("{{" + prefix + n.Name)
+			for _, arg := range n.Args {
+				b.WriteString(` "` + arg + `"`)
+			}
+			b.WriteString("}}")
+			writeMessageNodes(b, n.Nodes)
+			if n.Else != nil {
+				b.WriteString("{{else}}")
+				writeMessageNodes(b, n.Else)
+			}
+			b.WriteString("{{/" + n.Name + "}}")
+		}
+	}
+}
+
+// Variables returns the sorted names of the variables used by the template,
+// each once.
+func (t *MessageTemplate) Variables() []string {
+	seen := make(map[string]bool)
+	var names []string
+	walkMessageNodes(t.Nodes, func(node MessageNode) {
+		var name string
+		switch v := node.(type) {
+		case MessageVariable:
+			name = string(v)
+		case MessageRawVariable:
+			name = string(v)
+		default:
+			return
+		}
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	})
+	sort.Strings(names)
+	return names
+}
+
+func walkMessageNodes(nodes []MessageNode, fn func(MessageNode)) {
+	for _, node := range nodes {
+		fn(node)
+		if section, ok := node.(MessageSection); ok {
+			walkMessageNodes(section.Nodes, fn)
+			walkMessageNodes(section.Else, fn)
+		}
+	}
+}
+
+// NotificationTarget is an @-handle of a message, e.g. @slack-ops.
+type NotificationTarget struct {
+	// Handle is the handle without the @, e.g. "slack-ops".
+	Handle string
+	// Integration is the integration notified, e.g. "slack" or "pagerduty",
+	// "email" for email addresses, or "" for handles of no known integration.
+	Integration string
+	// Name is the handle without the integration prefix, e.g. "ops".
+	Name string
+}
+
+// notificationHandleRegex matches @-handles, which start a word and may be
+// email addresses.
+var notificationHandleRegex = regexp.MustCompile(`(?:^|[\s(\[,;])@([\w.+-]+(?:@[\w-]+(?:\.[\w-]+)+)?)`)
+
+// notificationIntegrations are the prefixes of @-handles naming an
+// integration.
+var notificationIntegrations = []string{"slack", "pagerduty", "webhook", "opsgenie", "victorops", "teams", "jira", "servicenow", "oncall"}
+
+// Targets returns the @-handles of the template, in order and each once,
+// including the ones inside sections.
+func (t *MessageTemplate) Targets() []NotificationTarget {
+	seen := make(map[string]bool)
+	var targets []NotificationTarget
+	walkMessageNodes(t.Nodes, func(node MessageNode) {
+		text, ok := node.(MessageText)
+		if !ok {
+			return
+		}
+		for _, match := range notificationHandleRegex.FindAllStringSubmatch(string(text), -1) {
+			handle := strings.TrimRight(match[1], ".")
+			if seen[handle] {
+				continue
+			}
+			seen[handle] = true
+			targets = append(targets, newNotificationTarget(handle))
+		}
+	})
+	return targets
+}
+
+func newNotificationTarget(handle string) NotificationTarget {
+	target := NotificationTarget{Handle: handle, Name: handle}
+	if strings.Contains(handle, "@") {
+		target.Integration = "email"
+		return target
+	}
+	for _, integration := range notificationIntegrations {
+		if strings.HasPrefix(handle, integration+"-") {
+			target.Integration = integration
+			target.Name = handle[len(integration)+1:]
+			break
+		}
+	}
+	return target
+}
+
+// NotificationIntegrations are the Slack channels and PagerDuty services
+// notifications can be sent to, to check the targets of messages against.
+type NotificationIntegrations struct {
+	// Slack is the configuration of the Slack integration, as sent to
+	// CreateIntegrationSlack, or nil not to check Slack handles.
+	Slack *IntegrationSlackRequest
+	// PagerDutyServices are the service names of the PagerDuty
+	// integration, or nil not to check PagerDuty handles.
+	PagerDutyServices []string
+}
+
+// Missing returns the Slack and PagerDuty targets that do not match any
+// channel or service of the integrations. Slack handles may name the channel
+// alone, e.g. @slack-ops, or prefix it with the account, e.g.
+// @slack-myteam-ops.
+func (i *NotificationIntegrations) Missing(targets []NotificationTarget) []NotificationTarget {
+	var missing []NotificationTarget
+	for _, target := range targets {
+		switch {
+		case target.Integration == "slack" && i.Slack != nil:
+			if !i.hasSlackChannel(target.Name) {
+				missing = append(missing, target)
+			}
+		case target.Integration == "pagerduty" && i.PagerDutyServices != nil:
+			found := false
+			for _, service := range i.PagerDutyServices {
+				found = found || service == target.Name
+			}
+			if !found {
+				missing = append(missing, target)
+			}
+		}
+	}
+	return missing
+}
+
+func (i *NotificationIntegrations) hasSlackChannel(name string) bool {
+	for _, channel := range i.Slack.Channels {
+		channelName := strings.TrimPrefix(channel.GetChannelName(), "#")
+		if name == channelName || name == channel.GetAccount()+"-"+channelName {
+			return true
+		}
+	}
+	return false
+}
+
+// MessageContext is the state a message is rendered for.
+type MessageContext struct {
+	// Status is the state of the monitor, or MonitorStatusOK on recovery.
+	Status MonitorStatus
+	// PreviousStatus is the state the monitor moved from, telling
+	// recoveries apart.
+	PreviousStatus MonitorStatus
+	// Renotify is true for renotifications.
+	Renotify bool
+	// Priority is the priority of the monitor, e.g. "P1".
+	Priority string
+	// Variables holds the values of the template variables, e.g. "value"
+	// or "host.name". Missing variables render empty.
+	Variables map[string]string
+}
+
+// Render renders the template for a state, like Datadog does when it sends
+// a notification. It fails on sections with an unknown condition or the
+// wrong number of arguments, which templates built by hand may have.
+func (t *MessageTemplate) Render(ctx MessageContext) (string, error) {
+	var b bytes.Buffer
+	if err := renderMessageNodes(&b, t.Nodes, ctx); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+func renderMessageNodes(b *bytes.Buffer, nodes []MessageNode, ctx MessageContext) error {
+	for _, node := range nodes {
+		switch n := node.(type) {
+		case MessageText:
+			b.WriteString(string(n))
+		case MessageVariable:
+			b.WriteString(ctx.Variables[string(n)])
+		case MessageRawVariable:
+			b.WriteString(ctx.Variables[string(n)])
+		case MessageSection:
+			matches, err := ctx.matches(n)
+			if err != nil {
+				return err
+			}
+			branch := n.Nodes
+			if matches == n.Inverted {
+				branch = n.Else
+			}
+			if err := renderMessageNodes(b, branch, ctx); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// matches returns true if the condition of section holds, or an error if the
+// section is not a valid condition.
+func (ctx MessageContext) matches(section MessageSection) (bool, error) {
+	if err := checkMessageCondition(section); err != nil {
+		return false, fmt.Errorf("invalid section {{#%s}}: %s", section.Name, err)
+	}
+	recovery := ctx.Status == MonitorStatusOK
+	switch section.Name {
+	case "is_alert":
+		return ctx.Status == MonitorStatusAlert, nil
+	case "is_warning":
+		return ctx.Status == MonitorStatusWarn, nil
+	case "is_no_data":
+		return ctx.Status == MonitorStatusNoData, nil
+	case "is_recovery":
+		return recovery, nil
+	case "is_alert_recovery":
+		return recovery && ctx.PreviousStatus == MonitorStatusAlert, nil
+	case "is_warning_recovery":
+		return recovery && ctx.PreviousStatus == MonitorStatusWarn, nil
+	case "is_no_data_recovery":
+		return recovery && ctx.PreviousStatus == MonitorStatusNoData, nil
+	case "is_alert_to_warning":
+		return ctx.Status == MonitorStatusWarn && ctx.PreviousStatus == MonitorStatusAlert, nil
+	case "is_renotify":
+		return ctx.Renotify, nil
+	case "is_priority":
+		return strings.EqualFold(ctx.Priority, section.Args[0]), nil
+	case "is_match", "is_exact_match":
+		value := ctx.Variables[section.Args[0]]
+		for _, want := range section.Args[1:] {
+			if (section.Name == "is_match" && strings.Contains(value, want)) || value == want {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
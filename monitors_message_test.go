@@ -0,0 +1,186 @@
+package datadog_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	dd "github.com/zorkian/go-datadog-api"
+)
+
+const testMessage = `{{#is_alert}}CPU is at {{value}} on {{host.name}} @slack-ops @pagerduty-Web{{/is_alert}}
+{{#is_warning}}CPU is high @slack-myteam-alerts{{/is_warning}}
+{{^is_recovery}}{{#is_match "host.name" "db" "cache"}}Database hosts: @dba@example.com.{{/is_match}}{{/is_recovery}}
+{{#is_alert_recovery}}Back to normal @slack-ops{{/is_alert_recovery}}
+{{#is_priority 'P1'}}@oncall-web{{/is_priority}}`
+
+func TestParseMessageTemplate(t *testing.T) {
+	tmpl, err := dd.ParseMessageTemplate("{{ #is_alert }}{{ value }} @slack-ops{{/is_alert}}")
+	assert.Nil(t, err)
+	assert.Equal(t, []dd.MessageNode{
+		dd.MessageSection{Name: "is_alert", Args: []string{}, Nodes: []dd.MessageNode{
+			dd.MessageVariable("value"),
+			dd.MessageText(" @slack-ops"),
+		}},
+	}, tmpl.Nodes)
+	assert.Equal(t, "{{#is_alert}}{{value}} @slack-ops{{/is_alert}}", tmpl.String())
+
+	tmpl, err = dd.ParseMessageTemplate(testMessage)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"host.name", "value"}, tmpl.Variables())
+
+	for message, want := range map[string]string{
+		"{{value":                             "unclosed {{ at offset 0",
+		"{{#is_alert}}":                       "{{#is_alert}} at offset 0 is not closed",
+		"ok {{/is_alert}}":                    "{{/is_alert}} closes no section at offset 3",
+		"{{#is_alert}}{{/is_warning}}":        "{{/is_warning}} at offset 13 does not close {{#is_alert}}",
+		"{{#is_alret}}{{/is_alret}}":          `unknown condition "is_alret" at offset 0`,
+		"{{#is_match \"host\"}}{{/is_match}}": `condition "is_match" takes at least 2 arguments at offset 0`,
+		"{{host name}}":                       "invalid variable {{host name}} at offset 0",
+	} {
+		_, err := dd.ParseMessageTemplate(message)
+		if assert.NotNil(t, err, message) {
+			assert.Equal(t, want, err.Error())
+		}
+	}
+}
+
+func TestMessageTemplateTargets(t *testing.T) {
+	tmpl, err := dd.ParseMessageTemplate(testMessage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	targets := tmpl.Targets()
+	assert.Equal(t, []dd.NotificationTarget{
+		{Handle: "slack-ops", Integration: "slack", Name: "ops"},
+		{Handle: "pagerduty-Web", Integration: "pagerduty", Name: "Web"},
+		{Handle: "slack-myteam-alerts", Integration: "slack", Name: "myteam-alerts"},
+		{Handle: "dba@example.com", Integration: "email", Name: "dba@example.com"},
+		{Handle: "oncall-web", Integration: "oncall", Name: "web"},
+	}, targets)
+
+	integrations := &dd.NotificationIntegrations{
+		Slack: &dd.IntegrationSlackRequest{Channels: []dd.ChannelSlackRequest{
+			{ChannelName: dd.String("#ops"), Account: dd.String("myteam")},
+		}},
+		PagerDutyServices: []string{"Web"},
+	}
+	assert.Equal(t, []dd.NotificationTarget{
+		{Handle: "slack-myteam-alerts", Integration: "slack", Name: "myteam-alerts"},
+	}, integrations.Missing(targets))
+}
+
+// mustRender renders tmpl for ctx, failing the test on errors.
+func mustRender(t *testing.T, tmpl *dd.MessageTemplate, ctx dd.MessageContext) string {
+	out, err := tmpl.Render(ctx)
+	assert.Nil(t, err)
+	return out
+}
+
+func TestMessageTemplateRender(t *testing.T) {
+	tmpl, err := dd.ParseMessageTemplate(testMessage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vars := map[string]string{"value": "97.5", "host.name": "db-1"}
+
+	assert.Equal(t, "CPU is at 97.5 on db-1 @slack-ops @pagerduty-Web\n\nDatabase hosts: @dba@example.com.\n\n@oncall-web",
+		mustRender(t, tmpl, dd.MessageContext{Status: dd.MonitorStatusAlert, Priority: "P1", Variables: vars}))
+	assert.Equal(t, "\nCPU is high @slack-myteam-alerts\nDatabase hosts: @dba@example.com.\n\n",
+		mustRender(t, tmpl, dd.MessageContext{Status: dd.MonitorStatusWarn, Variables: vars}))
+	assert.Equal(t, "\n\n\nBack to normal @slack-ops\n",
+		mustRender(t, tmpl, dd.MessageContext{Status: dd.MonitorStatusOK, PreviousStatus: dd.MonitorStatusAlert, Variables: vars}))
+	assert.Equal(t, "\nCPU is high @slack-myteam-alerts\n\n\n",
+		mustRender(t, tmpl, dd.MessageContext{Status: dd.MonitorStatusWarn, Variables: map[string]string{"host.name": "web-1"}}))
+}
+
+func TestMessageTemplateRenderInvalidSections(t *testing.T) {
+	for _, section := range []dd.MessageSection{
+		{Name: "is_priority"},
+		{Name: "is_match", Args: []string{"host.name"}},
+		{Name: "is_exact_match"},
+		{Name: "is_down"},
+	} {
+		tmpl := &dd.MessageTemplate{Nodes: []dd.MessageNode{section}}
+		_, err := tmpl.Render(dd.MessageContext{Status: dd.MonitorStatusAlert})
+		assert.NotNil(t, err, section.Name)
+	}
+
+	tmpl := &dd.MessageTemplate{Nodes: []dd.MessageNode{dd.MessageSection{Name: "is_alert", Else: []dd.MessageNode{
+		dd.MessageSection{Name: "is_priority"},
+	}}}}
+	_, err := tmpl.Render(dd.MessageContext{Status: dd.MonitorStatusWarn})
+	if assert.NotNil(t, err) {
+		assert.Equal(t, `invalid section {{#is_priority}}: condition "is_priority" takes 1 arguments, not 0`, err.Error())
+	}
+}
+
+func TestMessageTemplateElse(t *testing.T) {
+	tmpl, err := dd.ParseMessageTemplate("{{#is_alert}}down @slack-ops{{ else }}{{^is_recovery}}warn{{else}}up{{/is_recovery}} @slack-info{{/is_alert}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []dd.MessageNode{
+		dd.MessageSection{Name: "is_alert", Args: []string{},
+			Nodes: []dd.MessageNode{dd.MessageText("down @slack-ops")},
+			Else: []dd.MessageNode{
+				dd.MessageSection{Name: "is_recovery", Args: []string{}, Inverted: true,
+					Nodes: []dd.MessageNode{dd.MessageText("warn")},
+					Else:  []dd.MessageNode{dd.MessageText("up")},
+				},
+				dd.MessageText(" @slack-info"),
+			},
+		},
+	}, tmpl.Nodes)
+	assert.Equal(t, "{{#is_alert}}down @slack-ops{{else}}{{^is_recovery}}warn{{else}}up{{/is_recovery}} @slack-info{{/is_alert}}", tmpl.String())
+	assert.Equal(t, []string{"slack-ops", "slack-info"}, []string{tmpl.Targets()[0].Handle, tmpl.Targets()[1].Handle})
+
+	assert.Equal(t, "down @slack-ops", mustRender(t, tmpl, dd.MessageContext{Status: dd.MonitorStatusAlert}))
+	assert.Equal(t, "warn @slack-info", mustRender(t, tmpl, dd.MessageContext{Status: dd.MonitorStatusWarn}))
+	assert.Equal(t, "up @slack-info", mustRender(t, tmpl, dd.MessageContext{Status: dd.MonitorStatusOK}))
+
+	// An empty else branch is kept.
+	tmpl, err = dd.ParseMessageTemplate("{{#is_alert}}a{{else}}{{/is_alert}}")
+	if assert.Nil(t, err) {
+		assert.Equal(t, "{{#is_alert}}a{{else}}{{/is_alert}}", tmpl.String())
+	}
+
+	for message, want := range map[string]string{
+		"a {{else}} b": "{{else}} outside of a section at offset 2",
+		"{{#is_alert}}a{{else}}b{{else}}c{{/is_alert}}": "second {{else}} of {{#is_alert}} at offset 23",
+	} {
+		_, err := dd.ParseMessageTemplate(message)
+		if assert.NotNil(t, err, message) {
+			assert.Equal(t, want, err.Error())
+		}
+	}
+}
+
+func TestMessageTemplateRawVariables(t *testing.T) {
+	tmpl, err := dd.ParseMessageTemplate("{{#is_alert}}{{{ host.name }}} is at {{value}}{{/is_alert}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []dd.MessageNode{
+		dd.MessageSection{Name: "is_alert", Args: []string{}, Nodes: []dd.MessageNode{
+			dd.MessageRawVariable("host.name"),
+			dd.MessageText(" is at "),
+			dd.MessageVariable("value"),
+		}},
+	}, tmpl.Nodes)
+	assert.Equal(t, "{{#is_alert}}{{{host.name}}} is at {{value}}{{/is_alert}}", tmpl.String())
+	assert.Equal(t, []string{"host.name", "value"}, tmpl.Variables())
+	assert.Equal(t, "<b>web-1</b> is at 97", mustRender(t, tmpl, dd.MessageContext{
+		Status:    dd.MonitorStatusAlert,
+		Variables: map[string]string{"host.name": "<b>web-1</b>", "value": "97"},
+	}))
+
+	for message, want := range map[string]string{
+		"ok {{{host.name}}": "unclosed {{{ at offset 3",
+		"{{{host name}}}":   "invalid variable {{{host name}}} at offset 0",
+	} {
+		_, err := dd.ParseMessageTemplate(message)
+		if assert.NotNil(t, err, message) {
+			assert.Equal(t, want, err.Error())
+		}
+	}
+}
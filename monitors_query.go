@@ -0,0 +1,721 @@
+/*
+ * Datadog API for Go
+ *
+ * Please see the included LICENSE file for licensing information.
+ *
+ * Copyright 2020 by authors and contributors.
+ */
+
+package datadog
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// MonitorQuery is the structured form of a Monitor.Query. String renders the
+// query as the API expects it, and Validate reports mistakes such as unknown
+// aggregators or malformed time windows.
+type MonitorQuery interface {
+	String() string
+	Validate() error
+}
+
+// ParseMonitorQuery parses the query of a monitor of the given type, e.g.
+// "metric alert". Parsing a query and rendering it back with String gives the
+// same query, apart from the spacing around operators.
+func ParseMonitorQuery(monitorType, query string) (MonitorQuery, error) {
+	switch monitorType {
+	case "metric alert", "query alert":
+		return ParseMetricAlertQuery(query)
+	case "service check":
+		return ParseServiceCheckQuery(query)
+	case "event alert":
+		return ParseEventAlertQuery(query)
+	case "composite":
+		return ParseCompositeQuery(query)
+	}
+	return nil, fmt.Errorf("no query parser for monitor type %q", monitorType)
+}
+
+// Aggregators of metric alert queries.
+const (
+	MonitorAggregatorAvg = "avg"
+	MonitorAggregatorSum = "sum"
+	MonitorAggregatorMin = "min"
+	MonitorAggregatorMax = "max"
+)
+
+// Change functions of metric alert queries, which alert on the change of a
+// metric over ChangeShift instead of its value.
+const (
+	MonitorChange        = "change"
+	MonitorPercentChange = "pct_change"
+)
+
+var (
+	monitorAggregators = map[string]bool{
+		MonitorAggregatorAvg: true,
+		MonitorAggregatorSum: true,
+		MonitorAggregatorMin: true,
+		MonitorAggregatorMax: true,
+	}
+	monitorComparators = map[string]bool{">": true, ">=": true, "<": true, "<=": true}
+
+	// monitorPercentileRegex matches the percentile space aggregators of
+	// distribution metrics, e.g. "p99".
+	monitorPercentileRegex = regexp.MustCompile(`^p(?:100|[1-9]?[0-9])$`)
+	// monitorTimeWindowRegex matches evaluation windows, either rolling,
+	// e.g. "last_5m", or calendar aligned, e.g. "current_1mo".
+	monitorTimeWindowRegex = regexp.MustCompile(`^(?:last|current)_\d+(?:mo|[smhdwy])$`)
+	monitorMetricRegex     = regexp.MustCompile(`^[A-Za-z][\w.]*$`)
+
+	// monitorComparisonRegex matches the comparison ending a query, e.g.
+	// "> 90".
+	monitorComparisonRegex = regexp.MustCompile(`\s*(>=|<=|>|<)\s*(-?[0-9]*\.?[0-9]+(?:[eE][-+]?[0-9]+)?)$`)
+	// metricEvaluationRegex matches the evaluation of a metric alert query,
+	// e.g. "avg(last_5m):" or "pct_change(avg(last_5m),last_1h):".
+	metricEvaluationRegex = regexp.MustCompile(`^(?:(\w+)\()?(\w+)\((\w+)\)(?:,(\w+)\))?:`)
+	// metricExpressionRegex matches a single metric with its scope and
+	// grouping, e.g. "sum:foo{env:prod} by {host}".
+	metricExpressionRegex = regexp.MustCompile(`^(\w+):([\w.]+)\{([^{}]*)\}(?: by \{([^{}]*)\})?$`)
+)
+
+// MetricAlertQuery is the query of a metric alert monitor, e.g.
+//
+//	avg(last_5m):sum:foo{env:prod} by {host} > 90
+//
+// The metric is given either by SpaceAggregator, Metric, Scope and GroupBy,
+// or as a whole by Expression for queries with functions or arithmetic.
+type MetricAlertQuery struct {
+	// Change is MonitorChange or MonitorPercentChange to alert on the change
+	// of the metric over ChangeShift, e.g. "last_1h".
+	Change      string
+	ChangeShift string
+
+	// TimeAggregator aggregates the metric over TimeWindow, e.g. "last_5m".
+	TimeAggregator string
+	TimeWindow     string
+
+	SpaceAggregator string
+	Metric          string
+	// Scope is the list of tags to filter on; no tags means "*".
+	Scope   []string
+	GroupBy []string
+
+	// Expression, if set, replaces SpaceAggregator, Metric, Scope and
+	// GroupBy.
+	Expression string
+
+	// Comparator is one of ">", ">=", "<" and "<=".
+	Comparator string
+	Threshold  json.Number
+}
+
+// ParseMetricAlertQuery parses the query of a metric alert monitor.
+func ParseMetricAlertQuery(query string) (*MetricAlertQuery, error) {
+	q := &MetricAlertQuery{}
+	rest := strings.TrimSpace(query)
+
+	match := metricEvaluationRegex.FindStringSubmatch(rest)
+	if match == nil {
+		return nil, fmt.Errorf("invalid metric alert query %q: must start with an evaluation such as avg(last_5m):", query)
+	}
+	if (match[1] == "") != (match[4] == "") {
+		return nil, fmt.Errorf("invalid metric alert query %q: malformed change evaluation", query)
+	}
+	q.Change, q.TimeAggregator, q.TimeWindow, q.ChangeShift = match[1], match[2], match[3], match[4]
+	rest = rest[len(match[0]):]
+
+	var ok bool
+	if rest, q.Comparator, q.Threshold, ok = splitQueryComparison(rest); !ok {
+		return nil, fmt.Errorf("invalid metric alert query %q: must end with a comparison such as > 90", query)
+	}
+
+	if match = metricExpressionRegex.FindStringSubmatch(rest); match != nil {
+		simple := MetricAlertQuery{
+			SpaceAggregator: match[1],
+			Metric:          match[2],
+			Scope:           splitQueryList(match[3], "*"),
+			GroupBy:         splitQueryList(match[4], ""),
+		}
+		// Only keep the expression in pieces if that does not change it,
+		// e.g. by dropping spaces between tags.
+		if simple.expression() == rest {
+			q.SpaceAggregator, q.Metric, q.Scope, q.GroupBy = simple.SpaceAggregator, simple.Metric, simple.Scope, simple.GroupBy
+			return q, nil
+		}
+	}
+	q.Expression = rest
+	return q, nil
+}
+
+// splitQueryComparison splits the comparison ending a metric, log or event
+// alert query, e.g. "> 90", from the rest of the query.
+func splitQueryComparison(query string) (rest, comparator string, threshold json.Number, ok bool) {
+	match := monitorComparisonRegex.FindStringSubmatch(query)
+	if match == nil {
+		return query, "", "", false
+	}
+	return query[:len(query)-len(match[0])], match[1], json.Number(match[2]), true
+}
+
+// String renders the query.
+func (q *MetricAlertQuery) String() string {
+	evaluation := fmt.Sprintf("%s(%s)", q.TimeAggregator, q.TimeWindow)
+	if q.Change != "" {
+		evaluation = fmt.Sprintf("%s(%s,%s)", q.Change, evaluation, q.ChangeShift)
+	}
+	return fmt.Sprintf("%s:%s %s %s", evaluation, q.expression(), q.Comparator, q.Threshold)
+}
+
+func (q *MetricAlertQuery) expression() string {
+	if q.Expression != "" {
+		return q.Expression
+	}
+	scope := "*"
+	if len(q.Scope) > 0 {
+		scope = strings.Join(q.Scope, ",")
+	}
+	expression := fmt.Sprintf("%s:%s{%s}", q.SpaceAggregator, q.Metric, scope)
+	if len(q.GroupBy) > 0 {
+		expression += fmt.Sprintf(" by {%s}", strings.Join(q.GroupBy, ","))
+	}
+	return expression
+}
+
+// Validate checks the aggregators, time windows, metric and comparison of the
+// query.
+func (q *MetricAlertQuery) Validate() error {
+	var errs []string
+	if q.Change != "" {
+		if q.Change != MonitorChange && q.Change != MonitorPercentChange {
+			errs = append(errs, fmt.Sprintf("unknown change function %q", q.Change))
+		}
+		if !monitorTimeWindowRegex.MatchString(q.ChangeShift) {
+			errs = append(errs, fmt.Sprintf("invalid change shift %q, e.g. last_1h", q.ChangeShift))
+		}
+	}
+	if !monitorAggregators[q.TimeAggregator] {
+		errs = append(errs, fmt.Sprintf("unknown time aggregator %q", q.TimeAggregator))
+	}
+	if !monitorTimeWindowRegex.MatchString(q.TimeWindow) {
+		errs = append(errs, fmt.Sprintf("invalid time window %q, e.g. last_5m", q.TimeWindow))
+	}
+	if q.Expression == "" {
+		if !monitorAggregators[q.SpaceAggregator] && !monitorPercentileRegex.MatchString(q.SpaceAggregator) {
+			errs = append(errs, fmt.Sprintf("unknown space aggregator %q", q.SpaceAggregator))
+		}
+		if !monitorMetricRegex.MatchString(q.Metric) {
+			errs = append(errs, fmt.Sprintf("invalid metric name %q", q.Metric))
+		}
+	}
+	errs = append(errs, checkComparison(q.Comparator, q.Threshold)...)
+	return queryValidationError("metric alert", errs)
+}
+
+var (
+	// serviceCheckQueryPartsRegex matches a service check query, capturing
+	// the check, the arguments of over, exclude and by, and the count.
+	serviceCheckQueryPartsRegex = regexp.MustCompile(`^"([^"]+)"\.over\(([^()]*)\)(?:\.exclude\(([^()]*)\))?(?:\.by\(([^()]*)\))?\.last\((\d+)\)\.count_by_status\(\)$`)
+	// queryArgumentsRegex matches a list of quoted arguments, e.g.
+	// "env:prod","role:db".
+	queryArgumentsRegex = regexp.MustCompile(`^(?:"[^"]*"(?:,"[^"]*")*)?$`)
+)
+
+// ServiceCheckQuery is the query of a service check monitor, e.g.
+//
+//	"datadog.agent.up".over("env:prod").by("host").last(2).count_by_status()
+type ServiceCheckQuery struct {
+	Check string
+	// Over is the list of tags to filter on; no tags means "*".
+	Over    []string
+	Exclude []string
+	By      []string
+	// Last is the number of consecutive statuses to alert on.
+	Last int
+}
+
+// ParseServiceCheckQuery parses the query of a service check monitor.
+func ParseServiceCheckQuery(query string) (*ServiceCheckQuery, error) {
+	match := serviceCheckQueryPartsRegex.FindStringSubmatch(strings.TrimSpace(query))
+	if match == nil {
+		return nil, fmt.Errorf(`invalid service check query %q: must look like "check".over(tags).last(count).count_by_status()`, query)
+	}
+	q := &ServiceCheckQuery{Check: match[1]}
+	var err error
+	if q.Over, err = parseQueryArguments(match[2]); err != nil {
+		return nil, fmt.Errorf("invalid service check query %q: %s", query, err)
+	}
+	if len(q.Over) == 1 && q.Over[0] == "*" {
+		q.Over = nil
+	}
+	if q.Exclude, err = parseQueryArguments(match[3]); err != nil {
+		return nil, fmt.Errorf("invalid service check query %q: %s", query, err)
+	}
+	if q.By, err = parseQueryArguments(match[4]); err != nil {
+		return nil, fmt.Errorf("invalid service check query %q: %s", query, err)
+	}
+	// The regular expression only matches digits.
+	q.Last, _ = strconv.Atoi(match[5])
+	return q, nil
+}
+
+// String renders the query.
+func (q *ServiceCheckQuery) String() string {
+	over := q.Over
+	if len(over) == 0 {
+		over = []string{"*"}
+	}
+	query := fmt.Sprintf(`"%s".over(%s)`, q.Check, quoteQueryArguments(over, `"`))
+	if len(q.Exclude) > 0 {
+		query += fmt.Sprintf(".exclude(%s)", quoteQueryArguments(q.Exclude, `"`))
+	}
+	if len(q.By) > 0 {
+		query += fmt.Sprintf(".by(%s)", quoteQueryArguments(q.By, `"`))
+	}
+	return query + fmt.Sprintf(".last(%d).count_by_status()", q.Last)
+}
+
+// Validate checks that the query has a check and a positive count.
+func (q *ServiceCheckQuery) Validate() error {
+	var errs []string
+	if q.Check == "" || strings.ContainsAny(q.Check, `"`) {
+		errs = append(errs, fmt.Sprintf("invalid check name %q", q.Check))
+	}
+	if q.Last < 1 {
+		errs = append(errs, "last must be at least 1")
+	}
+	return queryValidationError("service check", errs)
+}
+
+var (
+	// eventAlertQueryRegex matches an event alert query, capturing the event
+	// search, the chain of functions applied to it and the comparison.
+	eventAlertQueryRegex = regexp.MustCompile(`^events\('([^']*)'\)((?:\.\w+\([^()]*\))*)$`)
+	eventFunctionRegex   = regexp.MustCompile(`\.(\w+)\(([^()]*)\)`)
+	eventWindowRegex     = regexp.MustCompile(`^\d+(?:mo|[smhdwy])$`)
+)
+
+// eventAlertFunctions are the functions of an event alert query, in the order
+// they are rendered in unless Order says otherwise.
+var eventAlertFunctions = []string{"by", "rollup", "last"}
+
+// EventAlertQuery is the query of an event alert monitor, e.g.
+//
+//	events('sources:nagios status:error').by('host').rollup('count').last('1h') > 10
+type EventAlertQuery struct {
+	// Search is the event search, e.g. "sources:nagios status:error".
+	Search string
+	By     []string
+	// Rollup is the aggregation of the events, e.g. "count".
+	Rollup string
+	// Last is the evaluation window, e.g. "1h".
+	Last string
+	// Order is the order of the by, rollup and last functions in the query,
+	// as parsed. Functions it leaves out are rendered after the others, in
+	// the order by, rollup, last.
+	Order []string
+
+	// Comparator is one of ">", ">=", "<" and "<=".
+	Comparator string
+	Threshold  json.Number
+}
+
+// ParseEventAlertQuery parses the query of an event alert monitor.
+func ParseEventAlertQuery(query string) (*EventAlertQuery, error) {
+	q := &EventAlertQuery{}
+	rest, comparator, threshold, ok := splitQueryComparison(strings.TrimSpace(query))
+	if !ok {
+		return nil, fmt.Errorf("invalid event alert query %q: must end with a comparison such as > 10", query)
+	}
+	q.Comparator, q.Threshold = comparator, threshold
+
+	match := eventAlertQueryRegex.FindStringSubmatch(rest)
+	if match == nil {
+		return nil, fmt.Errorf("invalid event alert query %q: must look like events('search').rollup('count').last('1h')", query)
+	}
+	q.Search = match[1]
+	for _, function := range eventFunctionRegex.FindAllStringSubmatch(match[2], -1) {
+		args, err := parseQueryArguments(strings.Replace(function[2], "'", `"`, -1))
+		if err != nil {
+			return nil, fmt.Errorf("invalid event alert query %q: %s", query, err)
+		}
+		switch name := function[1]; {
+		case name == "by":
+			q.By = args
+		case (name == "rollup" || name == "last") && len(args) == 1:
+			if name == "rollup" {
+				q.Rollup = args[0]
+			} else {
+				q.Last = args[0]
+			}
+		default:
+			return nil, fmt.Errorf("invalid event alert query %q: unexpected %s(%s)", query, name, function[2])
+		}
+		q.Order = append(q.Order, function[1])
+	}
+	return q, nil
+}
+
+// String renders the query, with its functions in Order.
+func (q *EventAlertQuery) String() string {
+	query := fmt.Sprintf("events('%s')", q.Search)
+	rendered := make(map[string]bool)
+	for _, name := range append(append([]string(nil), q.Order...), eventAlertFunctions...) {
+		if rendered[name] {
+			continue
+		}
+		rendered[name] = true
+		switch {
+		case name == "by" && len(q.By) > 0:
+			query += fmt.Sprintf(".by(%s)", quoteQueryArguments(q.By, "'"))
+		case name == "rollup" && q.Rollup != "":
+			query += fmt.Sprintf(".rollup('%s')", q.Rollup)
+		case name == "last" && q.Last != "":
+			query += fmt.Sprintf(".last('%s')", q.Last)
+		}
+	}
+	return fmt.Sprintf("%s %s %s", query, q.Comparator, q.Threshold)
+}
+
+// Validate checks the search, window and comparison of the query.
+func (q *EventAlertQuery) Validate() error {
+	var errs []string
+	if strings.Contains(q.Search, "'") {
+		errs = append(errs, "event search must not contain single quotes")
+	}
+	if q.Rollup == "" {
+		errs = append(errs, "rollup is required, e.g. count")
+	}
+	if !eventWindowRegex.MatchString(q.Last) {
+		errs = append(errs, fmt.Sprintf("invalid window %q, e.g. 1h", q.Last))
+	}
+	for _, name := range q.Order {
+		if name != "by" && name != "rollup" && name != "last" {
+			errs = append(errs, fmt.Sprintf("unknown function %q in order", name))
+		}
+	}
+	errs = append(errs, checkComparison(q.Comparator, q.Threshold)...)
+	return queryValidationError("event alert", errs)
+}
+
+// CompositeExpr is a node of a composite monitor query: a monitor ID, or a
+// combination of other nodes.
+type CompositeExpr interface {
+	String() string
+	compositeExpr()
+}
+
+// CompositeMonitor is the ID of a monitor in a composite query.
+type CompositeMonitor int
+
+// CompositeNotExpr negates an expression, e.g. "!12".
+type CompositeNotExpr struct {
+	Expr CompositeExpr
+}
+
+// CompositeBinaryExpr combines two expressions with "&&" or "||".
+type CompositeBinaryExpr struct {
+	Op          string
+	Left, Right CompositeExpr
+}
+
+// CompositeParenExpr is an expression in parentheses, e.g. "(12 || 34)".
+// Parentheses needed for precedence are added when rendering, so this is
+// only used to keep the ones of a parsed query.
+type CompositeParenExpr struct {
+	Expr CompositeExpr
+}
+
+func (CompositeMonitor) compositeExpr()    {}
+func (CompositeNotExpr) compositeExpr()    {}
+func (CompositeBinaryExpr) compositeExpr() {}
+func (CompositeParenExpr) compositeExpr()  {}
+
+func (e CompositeMonitor) String() string   { return strconv.Itoa(int(e)) }
+func (e CompositeParenExpr) String() string { return "(" + e.Expr.String() + ")" }
+
+func (e CompositeNotExpr) String() string {
+	if _, ok := e.Expr.(CompositeBinaryExpr); ok {
+		return "!(" + e.Expr.String() + ")"
+	}
+	return "!" + e.Expr.String()
+}
+
+func (e CompositeBinaryExpr) String() string {
+	left, right := e.Left.String(), e.Right.String()
+	// Operands binding looser than the operator need parentheses, and so
+	// does a right operand with the same operator as operators associate to
+	// the left.
+	if l, ok := e.Left.(CompositeBinaryExpr); ok && compositePrecedence(l.Op) < compositePrecedence(e.Op) {
+		left = "(" + left + ")"
+	}
+	if r, ok := e.Right.(CompositeBinaryExpr); ok && compositePrecedence(r.Op) <= compositePrecedence(e.Op) {
+		right = "(" + right + ")"
+	}
+	return left + " " + e.Op + " " + right
+}
+
+func compositePrecedence(op string) int {
+	if op == "&&" {
+		return 2
+	}
+	return 1
+}
+
+// CompositeAnd combines expressions so that all of them must alert.
+func CompositeAnd(exprs ...CompositeExpr) CompositeExpr {
+	return compositeChain("&&", exprs)
+}
+
+// CompositeOr combines expressions so that any of them must alert.
+func CompositeOr(exprs ...CompositeExpr) CompositeExpr {
+	return compositeChain("||", exprs)
+}
+
+// CompositeNot negates an expression.
+func CompositeNot(expr CompositeExpr) CompositeExpr {
+	return CompositeNotExpr{Expr: expr}
+}
+
+func compositeChain(op string, exprs []CompositeExpr) CompositeExpr {
+	if len(exprs) == 0 {
+		return nil
+	}
+	expr := exprs[0]
+	for _, right := range exprs[1:] {
+		expr = CompositeBinaryExpr{Op: op, Left: expr, Right: right}
+	}
+	return expr
+}
+
+// CompositeQuery is the query of a composite monitor, e.g. "(12 && 34) || !56".
+type CompositeQuery struct {
+	Expr CompositeExpr
+}
+
+// ParseCompositeQuery parses the query of a composite monitor.
+func ParseCompositeQuery(query string) (*CompositeQuery, error) {
+	p := &compositeParser{query: query}
+	p.next()
+	expr, err := p.parseOr()
+	if err == nil && p.token != "" {
+		err = fmt.Errorf("unexpected %q", p.token)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid composite query %q: %s", query, err)
+	}
+	return &CompositeQuery{Expr: expr}, nil
+}
+
+// String renders the query.
+func (q *CompositeQuery) String() string {
+	if q.Expr == nil {
+		return ""
+	}
+	return q.Expr.String()
+}
+
+// Validate checks that the query refers to at least one monitor, and only to
+// valid monitor IDs.
+func (q *CompositeQuery) Validate() error {
+	var errs []string
+	if q.Expr == nil {
+		errs = append(errs, "no monitors specified")
+	}
+	var check func(CompositeExpr)
+	check = func(expr CompositeExpr) {
+		switch e := expr.(type) {
+		case CompositeMonitor:
+			if e <= 0 {
+				errs = append(errs, fmt.Sprintf("invalid monitor ID %d", e))
+			}
+		case CompositeNotExpr:
+			check(e.Expr)
+		case CompositeParenExpr:
+			check(e.Expr)
+		case CompositeBinaryExpr:
+			if e.Op != "&&" && e.Op != "||" {
+				errs = append(errs, fmt.Sprintf("unknown operator %q", e.Op))
+			}
+			check(e.Left)
+			check(e.Right)
+		case nil:
+			errs = append(errs, "missing operand")
+		}
+	}
+	if q.Expr != nil {
+		check(q.Expr)
+	}
+	return queryValidationError("composite", errs)
+}
+
+// MonitorIDs returns the sorted IDs of the monitors the query refers to,
+// each once.
+func (q *CompositeQuery) MonitorIDs() []int {
+	seen := make(map[int]bool)
+	var ids []int
+	var walk func(CompositeExpr)
+	walk = func(expr CompositeExpr) {
+		switch e := expr.(type) {
+		case CompositeMonitor:
+			if !seen[int(e)] {
+				seen[int(e)] = true
+				ids = append(ids, int(e))
+			}
+		case CompositeNotExpr:
+			walk(e.Expr)
+		case CompositeParenExpr:
+			walk(e.Expr)
+		case CompositeBinaryExpr:
+			walk(e.Left)
+			walk(e.Right)
+		}
+	}
+	walk(q.Expr)
+	sort.Ints(ids)
+	return ids
+}
+
+// compositeParser is a recursive descent parser of composite queries, where
+// && binds tighter than ||.
+type compositeParser struct {
+	query string
+	pos   int
+	token string
+}
+
+// next moves to the next token: a number, an operator, a parenthesis, or ""
+// at the end of the query.
+func (p *compositeParser) next() {
+	for p.pos < len(p.query) && unicode.IsSpace(rune(p.query[p.pos])) {
+		p.pos++
+	}
+	start := p.pos
+	switch {
+	case p.pos == len(p.query):
+	case strings.HasPrefix(p.query[p.pos:], "&&"), strings.HasPrefix(p.query[p.pos:], "||"):
+		p.pos += 2
+	case p.query[p.pos] >= '0' && p.query[p.pos] <= '9':
+		for p.pos < len(p.query) && p.query[p.pos] >= '0' && p.query[p.pos] <= '9' {
+			p.pos++
+		}
+	default:
+		p.pos++
+	}
+	p.token = p.query[start:p.pos]
+}
+
+func (p *compositeParser) parseOr() (CompositeExpr, error) {
+	return p.parseBinary("||", p.parseAnd)
+}
+
+func (p *compositeParser) parseAnd() (CompositeExpr, error) {
+	return p.parseBinary("&&", p.parseUnary)
+}
+
+func (p *compositeParser) parseBinary(op string, operand func() (CompositeExpr, error)) (CompositeExpr, error) {
+	left, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	for p.token == op {
+		p.next()
+		right, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		left = CompositeBinaryExpr{Op: op, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *compositeParser) parseUnary() (CompositeExpr, error) {
+	switch token := p.token; {
+	case token == "!":
+		p.next()
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return CompositeNotExpr{Expr: expr}, nil
+	case token == "(":
+		p.next()
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.token != ")" {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		p.next()
+		return CompositeParenExpr{Expr: expr}, nil
+	case token != "" && token[0] >= '0' && token[0] <= '9':
+		id, err := strconv.Atoi(token)
+		if err != nil {
+			return nil, err
+		}
+		p.next()
+		return CompositeMonitor(id), nil
+	case token == "":
+		return nil, fmt.Errorf("unexpected end of query")
+	default:
+		return nil, fmt.Errorf("unexpected %q", token)
+	}
+}
+
+// checkComparison checks the comparator and threshold ending a query.
+func checkComparison(comparator string, threshold json.Number) []string {
+	var errs []string
+	if !monitorComparators[comparator] {
+		errs = append(errs, fmt.Sprintf("unknown comparator %q", comparator))
+	}
+	if _, err := threshold.Float64(); err != nil {
+		errs = append(errs, fmt.Sprintf("invalid threshold %q", threshold))
+	}
+	return errs
+}
+
+func queryValidationError(monitorType string, errs []string) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("invalid %s query: %s", monitorType, strings.Join(errs, "; "))
+}
+
+// splitQueryList splits a comma separated list, e.g. of tags. A list holding
+// only empty is returned as nil.
+func splitQueryList(list, empty string) []string {
+	if list == "" || list == empty {
+		return nil
+	}
+	items := strings.Split(list, ",")
+	for i := range items {
+		items[i] = strings.TrimSpace(items[i])
+	}
+	return items
+}
+
+// parseQueryArguments parses a comma separated list of double quoted
+// arguments.
+func parseQueryArguments(args string) ([]string, error) {
+	if args == "" {
+		return nil, nil
+	}
+	if !queryArgumentsRegex.MatchString(args) {
+		return nil, fmt.Errorf("malformed arguments %s", args)
+	}
+	args = args[1 : len(args)-1]
+	return strings.Split(args, `","`), nil
+}
+
+// quoteQueryArguments renders a list of arguments between the given quotes.
+func quoteQueryArguments(args []string, quote string) string {
+	return quote + strings.Join(args, quote+","+quote) + quote
+}
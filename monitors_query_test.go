@@ -0,0 +1,178 @@
+package datadog_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	dd "github.com/zorkian/go-datadog-api"
+)
+
+func TestMonitorQueryRoundTrip(t *testing.T) {
+	tests := []struct {
+		monitorType string
+		query       string
+	}{
+		{"metric alert", "avg(last_5m):sum:foo{env:prod} by {host} > 90"},
+		{"metric alert", "max(last_1h):avg:system.cpu.user{*} >= 0.5"},
+		{"query alert", "pct_change(avg(last_5m),last_1h):avg:foo{env:prod,role:db} by {host,env} < -50"},
+		{"query alert", "avg(last_10m):sum:foo{*}.as_count() / sum:bar{*}.as_count() > 2"},
+		{"query alert", "avg(last_5m):sum:foo{env:prod, role:db} > 1"},
+		{"query alert", "sum(current_1mo):sum:foo{*}.as_count() > 1000"},
+		{"query alert", "avg(last_30s):avg:foo{*} > 1"},
+		{"service check", `"datadog.agent.up".over("*").last(2).count_by_status()`},
+		{"service check", `"http.can_connect".over("env:prod","role:web").exclude("host:a").by("host","url").last(3).count_by_status()`},
+		{"event alert", `events('sources:nagios status:error').by('host').rollup('count').last('1h') > 10`},
+		{"event alert", `events('priority:all').rollup('count').last('5m') >= 1`},
+		{"event alert", `events('priority:all sources:nagios').rollup('count').by('host').last('5m') > 0`},
+		{"event alert", `events('priority:all').last('1mo').rollup('count') > 0`},
+		{"composite", "12 && 34"},
+		{"composite", "(12 && 34) || !56"},
+		{"composite", "12 || 34 && !(56 || 78)"},
+	}
+	for _, tt := range tests {
+		q, err := dd.ParseMonitorQuery(tt.monitorType, tt.query)
+		if assert.Nil(t, err, tt.query) {
+			assert.Equal(t, tt.query, q.String())
+			assert.Nil(t, q.Validate(), tt.query)
+		}
+	}
+}
+
+func TestParseMetricAlertQuery(t *testing.T) {
+	q, err := dd.ParseMetricAlertQuery("avg(last_5m):sum:foo{env:prod} by {host} > 90")
+	assert.Nil(t, err)
+	assert.Equal(t, &dd.MetricAlertQuery{
+		TimeAggregator:  "avg",
+		TimeWindow:      "last_5m",
+		SpaceAggregator: "sum",
+		Metric:          "foo",
+		Scope:           []string{"env:prod"},
+		GroupBy:         []string{"host"},
+		Comparator:      ">",
+		Threshold:       "90",
+	}, q)
+
+	q, err = dd.ParseMetricAlertQuery("change(max(last_5m),last_1h):avg:foo{*}>=1.5")
+	assert.Nil(t, err)
+	assert.Equal(t, "change", q.Change)
+	assert.Equal(t, "last_1h", q.ChangeShift)
+	assert.Nil(t, q.Scope)
+	assert.Equal(t, "change(max(last_5m),last_1h):avg:foo{*} >= 1.5", q.String())
+
+	for _, query := range []string{"sum:foo{*} > 1", "avg(last_5m):sum:foo{*}", "change(avg(last_5m):sum:foo{*} > 1"} {
+		_, err = dd.ParseMetricAlertQuery(query)
+		assert.NotNil(t, err, query)
+	}
+}
+
+func TestMetricAlertQueryValidate(t *testing.T) {
+	q := &dd.MetricAlertQuery{
+		TimeAggregator:  "average",
+		TimeWindow:      "5m",
+		SpaceAggregator: "sum",
+		Metric:          "foo",
+		Comparator:      "=>",
+		Threshold:       "90",
+	}
+	err := q.Validate()
+	if assert.NotNil(t, err) {
+		assert.Equal(t, `invalid metric alert query: unknown time aggregator "average"; `+
+			`invalid time window "5m", e.g. last_5m; unknown comparator "=>"`, err.Error())
+	}
+
+	q.TimeAggregator, q.TimeWindow, q.Comparator = dd.MonitorAggregatorAvg, "last_5m", ">"
+	assert.Nil(t, q.Validate())
+	assert.Equal(t, "avg(last_5m):sum:foo{*} > 90", q.String())
+
+	// Distribution metrics are aggregated by percentiles.
+	for _, aggregator := range []string{"p50", "p99", "p100"} {
+		q.SpaceAggregator = aggregator
+		assert.Nil(t, q.Validate(), aggregator)
+	}
+	for _, aggregator := range []string{"p", "p101", "p999", "p05"} {
+		q.SpaceAggregator = aggregator
+		assert.NotNil(t, q.Validate(), aggregator)
+	}
+
+	parsed, err := dd.ParseMetricAlertQuery("avg(last_5m):p99:foo{*} > 1")
+	if assert.Nil(t, err) {
+		assert.Equal(t, "p99", parsed.SpaceAggregator)
+		assert.Nil(t, parsed.Validate())
+	}
+}
+
+func TestParseServiceCheckQuery(t *testing.T) {
+	q, err := dd.ParseServiceCheckQuery(`"datadog.agent.up".over("env:prod").by("host").last(2).count_by_status()`)
+	assert.Nil(t, err)
+	assert.Equal(t, &dd.ServiceCheckQuery{
+		Check: "datadog.agent.up",
+		Over:  []string{"env:prod"},
+		By:    []string{"host"},
+		Last:  2,
+	}, q)
+
+	_, err = dd.ParseServiceCheckQuery(`"datadog.agent.up".last(2)`)
+	assert.NotNil(t, err)
+
+	assert.NotNil(t, (&dd.ServiceCheckQuery{Check: "foo"}).Validate())
+}
+
+func TestParseEventAlertQuery(t *testing.T) {
+	q, err := dd.ParseEventAlertQuery(`events('sources:nagios').by('host','env').rollup('count').last('1h') > 10`)
+	assert.Nil(t, err)
+	assert.Equal(t, &dd.EventAlertQuery{
+		Search:     "sources:nagios",
+		By:         []string{"host", "env"},
+		Rollup:     "count",
+		Last:       "1h",
+		Order:      []string{"by", "rollup", "last"},
+		Comparator: ">",
+		Threshold:  "10",
+	}, q)
+
+	// Functions missing from the order come last, in the usual order.
+	built := &dd.EventAlertQuery{Search: "a", By: []string{"host"}, Rollup: "count", Last: "1h", Order: []string{"last"}, Comparator: ">", Threshold: "1"}
+	assert.Equal(t, `events('a').last('1h').by('host').rollup('count') > 1`, built.String())
+	built.Order = nil
+	assert.Equal(t, `events('a').by('host').rollup('count').last('1h') > 1`, built.String())
+	built.Order = []string{"sort"}
+	assert.NotNil(t, built.Validate())
+
+	_, err = dd.ParseEventAlertQuery(`events('sources:nagios').sort('asc').last('1h') > 10`)
+	assert.NotNil(t, err)
+
+	q.Last = "last_1h"
+	assert.NotNil(t, q.Validate())
+}
+
+func TestCompositeQuery(t *testing.T) {
+	q, err := dd.ParseCompositeQuery("(12 && 34) || !56")
+	assert.Nil(t, err)
+	assert.Equal(t, dd.CompositeBinaryExpr{
+		Op: "||",
+		Left: dd.CompositeParenExpr{Expr: dd.CompositeBinaryExpr{
+			Op:    "&&",
+			Left:  dd.CompositeMonitor(12),
+			Right: dd.CompositeMonitor(34),
+		}},
+		Right: dd.CompositeNotExpr{Expr: dd.CompositeMonitor(56)},
+	}, q.Expr)
+	assert.Equal(t, []int{12, 34, 56}, q.MonitorIDs())
+
+	// Parentheses needed for precedence are added when rendering.
+	built := &dd.CompositeQuery{Expr: dd.CompositeAnd(
+		dd.CompositeOr(dd.CompositeMonitor(1), dd.CompositeMonitor(2)),
+		dd.CompositeNot(dd.CompositeAnd(dd.CompositeMonitor(3), dd.CompositeMonitor(1))),
+	)}
+	assert.Equal(t, "(1 || 2) && !(3 && 1)", built.String())
+	assert.Equal(t, []int{1, 2, 3}, built.MonitorIDs())
+	parsed, err := dd.ParseCompositeQuery(built.String())
+	assert.Nil(t, err)
+	assert.Equal(t, built.String(), parsed.String())
+
+	for _, query := range []string{"", "12 &&", "(12 || 34", "12 34", "12 & 34", "a || b"} {
+		_, err = dd.ParseCompositeQuery(query)
+		assert.NotNil(t, err, query)
+	}
+	assert.NotNil(t, (&dd.CompositeQuery{Expr: dd.CompositeMonitor(0)}).Validate())
+}
@@ -0,0 +1,99 @@
+/*
+ * Datadog API for Go
+ *
+ * Please see the included LICENSE file for licensing information.
+ *
+ * Copyright 2020 by authors and contributors.
+ */
+
+package datadog
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// MonitorStatus is the state of a monitor, or of one of its groups.
+type MonitorStatus string
+
+// The states a monitor or group can be in.
+const (
+	MonitorStatusOK      MonitorStatus = "OK"
+	MonitorStatusAlert   MonitorStatus = "Alert"
+	MonitorStatusWarn    MonitorStatus = "Warn"
+	MonitorStatusNoData  MonitorStatus = "No Data"
+	MonitorStatusIgnored MonitorStatus = "Ignored"
+	MonitorStatusSkipped MonitorStatus = "Skipped"
+	MonitorStatusUnknown MonitorStatus = "Unknown"
+)
+
+// GetMonitorState retrieves a monitor along with the state of its groups.
+// groupStates restricts the groups returned to the given states, e.g. "alert",
+// "warn" and "no data", or "all" for every group. No group states returns
+// none of the groups, like GetMonitor.
+func (client *Client) GetMonitorState(id int, groupStates []string) (*Monitor, error) {
+	uri := fmt.Sprintf("/v1/monitor/%d", id)
+	if len(groupStates) > 0 {
+		uri += "?" + url.Values{"group_states": {strings.Join(groupStates, ",")}}.Encode()
+	}
+
+	var out Monitor
+	if err := client.doJsonRequest("GET", uri, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GroupsWithStatus returns the groups in one of the given states, sorted by
+// name. The Name of each group is filled in from the key of Groups if the API
+// left it out.
+func (s State) GroupsWithStatus(statuses ...MonitorStatus) []GroupData {
+	wanted := make(map[MonitorStatus]bool, len(statuses))
+	for _, status := range statuses {
+		wanted[status] = true
+	}
+
+	var groups []GroupData
+	for name, group := range s.Groups {
+		if !wanted[group.GetStatus()] {
+			continue
+		}
+		if !group.HasName() {
+			group.SetName(name)
+		}
+		groups = append(groups, group)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].GetName() < groups[j].GetName()
+	})
+	return groups
+}
+
+// AlertingGroups returns the groups currently in the Alert state, sorted by
+// name. Their TriggeringValue holds the value that triggered the alert.
+func (s State) AlertingGroups() []GroupData {
+	return s.GroupsWithStatus(MonitorStatusAlert)
+}
+
+// LastTriggered returns the time the group last triggered, or the zero time
+// if it never did.
+func (g *GroupData) LastTriggered() time.Time {
+	return unixTime(g.GetLastTriggeredTs())
+}
+
+// LastResolved returns the time the group last resolved, or the zero time if
+// it never did.
+func (g *GroupData) LastResolved() time.Time {
+	return unixTime(g.GetLastResolvedTs())
+}
+
+// unixTime converts a timestamp in seconds, 0 being the zero time.
+func unixTime(ts int) time.Time {
+	if ts == 0 {
+		return time.Time{}
+	}
+	return time.Unix(int64(ts), 0)
+}
@@ -0,0 +1,69 @@
+package datadog_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	dd "github.com/zorkian/go-datadog-api"
+)
+
+func TestGetMonitorState(t *testing.T) {
+	var query string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/monitor/42", r.URL.Path)
+		query = r.URL.RawQuery
+		w.Write([]byte(`{
+			"id": 42,
+			"overall_state": "Alert",
+			"state": {
+				"groups": {
+					"host:b": {
+						"name": "host:b",
+						"status": "Alert",
+						"last_triggered_ts": 1600000000,
+						"triggering_value": {"from_ts": 1599999700, "to_ts": 1600000000, "value": 98}
+					},
+					"host:a": {"status": "Alert", "last_triggered_ts": 1600000100},
+					"host:c": {"name": "host:c", "status": "Warn"},
+					"host:d": {"name": "host:d", "status": "OK", "last_resolved_ts": 1600000200}
+				}
+			}
+		}`))
+	}))
+	defer ts.Close()
+
+	client := dd.NewClient("foo", "bar")
+	client.SetBaseUrl(ts.URL)
+
+	monitor, err := client.GetMonitorState(42, []string{"alert", "warn"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "group_states=alert%2Cwarn", query)
+	assert.Equal(t, dd.MonitorStatusAlert, monitor.GetOverallState())
+
+	alerting := monitor.State.AlertingGroups()
+	if assert.Len(t, alerting, 2) {
+		assert.Equal(t, "host:a", alerting[0].GetName())
+		assert.Equal(t, time.Unix(1600000100, 0), alerting[0].LastTriggered())
+		assert.False(t, alerting[0].HasTriggeringValue())
+
+		assert.Equal(t, "host:b", alerting[1].GetName())
+		assert.Equal(t, 98, alerting[1].TriggeringValue.GetValue())
+		assert.Equal(t, time.Unix(1600000000, 0), alerting[1].LastTriggered())
+	}
+
+	groups := monitor.State.GroupsWithStatus(dd.MonitorStatusWarn, dd.MonitorStatusOK)
+	if assert.Len(t, groups, 2) {
+		assert.Equal(t, "host:c", groups[0].GetName())
+		assert.True(t, groups[0].LastTriggered().IsZero())
+		assert.Equal(t, time.Unix(1600000200, 0), groups[1].LastResolved())
+	}
+
+	_, err = client.GetMonitorState(42, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "", query)
+}
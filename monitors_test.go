@@ -1,7 +1,13 @@
 package datadog_test
 
 import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
 	"testing"
+	"time"
 
 	"encoding/json"
 
@@ -129,3 +135,192 @@ func TestMonitorSerializationForLogAlert(t *testing.T) {
 	assert.Equal(t, json.Number("1539661166736"), *monitor.Options.QueryConfig.TimeRange.From)
 	assert.Equal(t, "env:develop", *monitor.Options.QueryConfig.QueryString)
 }
+
+func TestMonitorOptionsRoundTrip(t *testing.T) {
+	raw := `
+	{
+		"id": 91879,
+		"query": "avg(last_5m):sum:system.net.bytes_rcvd{*} by {host,cluster} > 100",
+		"type": "query alert",
+		"tags": [],
+		"priority": 2,
+		"restricted_roles": ["a3b1c2d4-0000-11ee-be56-0242ac120002"],
+		"options": {
+			"notify_by": ["cluster"],
+			"renotify_interval": 60,
+			"renotify_statuses": ["alert", "no data"],
+			"renotify_occurrences": 3,
+			"new_group_delay": 120,
+			"groupby_simple_monitor": true,
+			"on_missing_data": "show_and_notify_no_data",
+			"scheduling_options": {"evaluation_window": {"day_starts": "04:00"}},
+			"threshold_windows": {"trigger_window": "last_15m", "recovery_window": "last_15m"},
+			"variables": [{"name": "a", "data_source": "metrics"}],
+			"notification_preset_name": "hide_query"
+		}
+	}`
+
+	var monitor dd.Monitor
+	if err := json.Unmarshal([]byte(raw), &monitor); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, 2, monitor.GetPriority())
+	assert.Equal(t, []string{"cluster"}, monitor.Options.NotifyBy)
+	assert.Equal(t, []string{"alert", "no data"}, monitor.Options.RenotifyStatuses)
+	assert.Equal(t, 3, monitor.Options.GetRenotifyOccurrences())
+	assert.Equal(t, 120, monitor.Options.GetNewGroupDelay())
+	assert.Equal(t, true, monitor.Options.GetGroupbySimpleMonitor())
+	assert.Equal(t, "show_and_notify_no_data", monitor.Options.GetOnMissingData())
+	assert.Equal(t, "04:00", monitor.Options.SchedulingOptions.EvaluationWindow.GetDayStarts())
+
+	// Options unknown to the library are sent back as they were received.
+	monitor.Options.SetNewGroupDelay(300)
+	data, err := json.Marshal(&monitor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var options struct {
+		Options map[string]json.RawMessage `json:"options"`
+	}
+	if err := json.Unmarshal(data, &options); err != nil {
+		t.Fatal(err)
+	}
+	assert.JSONEq(t, `[{"name": "a", "data_source": "metrics"}]`, string(options.Options["variables"]))
+	assert.JSONEq(t, `"hide_query"`, string(options.Options["notification_preset_name"]))
+	assert.JSONEq(t, `300`, string(options.Options["new_group_delay"]))
+	assert.JSONEq(t, `{"evaluation_window": {"day_starts": "04:00"}}`, string(options.Options["scheduling_options"]))
+}
+
+func TestMonitorOptionsUnknownKeys(t *testing.T) {
+	raw := `{"notify_audit": true, "variables": [{"name":"a"}], "notification_preset_name": "hide_query"}`
+	var options dd.Options
+	if err := json.Unmarshal([]byte(raw), &options); err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, options.GetNotifyAudit())
+
+	data, err := json.Marshal(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.JSONEq(t, raw, string(data))
+
+	// Decoding the options sent gives back the same options.
+	var decoded dd.Options
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, options, decoded)
+
+	// Modeled options take precedence over unknown ones, and options built
+	// by hand have none.
+	options.SetNotifyAudit(false)
+	data, err = json.Marshal(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.JSONEq(t, `{"notify_audit": false, "variables": [{"name": "a"}], "notification_preset_name": "hide_query"}`, string(data))
+	data, err = json.Marshal(dd.Options{NotifyAudit: dd.Bool(true)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.JSONEq(t, `{"notify_audit": true}`, string(data))
+}
+
+func TestSearchMonitors(t *testing.T) {
+	var query string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/monitor/search", r.URL.Path)
+		query = r.URL.RawQuery
+		response, err := ioutil.ReadFile("./tests/fixtures/monitors/search_response.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write(response)
+	}))
+	defer ts.Close()
+
+	client := dd.NewClient("foo", "bar")
+	client.SetBaseUrl(ts.URL)
+
+	result, err := client.SearchMonitors(dd.MonitorSearchOpts{
+		MonitorQueryOpts: dd.MonitorQueryOpts{
+			Name:        dd.String("CPU"),
+			GroupStates: []string{"alert", "no data"},
+			Tags:        []string{"env:prod"},
+			MonitorTags: []string{"team:web"},
+			Page:        dd.Int(0),
+			PageSize:    dd.Int(30),
+		},
+		Query: dd.String("muted:false"),
+		Sort:  dd.String("name,asc"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, `group_states=alert%2Cno+data&page=0&per_page=30&query=%22CPU%22+scope%3A%22env%3Aprod%22+tag%3A%22team%3Aweb%22+muted%3Afalse&sort=name%2Casc`, query)
+	assert.Equal(t, 3, result.Metadata.GetTotalCount())
+	assert.Equal(t, "Alert", result.Counts.Status[0].GetName())
+	assert.Equal(t, 2, result.Counts.Status[0].GetCount())
+	assert.Equal(t, true, result.Counts.Muted[1].GetName())
+	assert.Equal(t, "env:prod", result.Counts.Tag[0].GetName())
+	assert.Equal(t, 3, result.Counts.Type[0].GetCount())
+	if assert.Len(t, result.Monitors, 3) {
+		assert.Equal(t, 1, result.Monitors[0].GetId())
+		assert.Equal(t, "slack-ops", result.Monitors[0].Notifications[0].GetHandle())
+		assert.Equal(t, 1581000000, result.Monitors[0].GetLastTriggeredTs())
+		assert.False(t, result.Monitors[1].HasLastTriggeredTs())
+	}
+}
+
+func TestMonitorPager(t *testing.T) {
+	const total = 7
+	var queries []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		pageSize, _ := strconv.Atoi(r.URL.Query().Get("page_size"))
+		var monitors []dd.Monitor
+		for id := page * pageSize; id < (page+1)*pageSize && id < total; id++ {
+			monitors = append(monitors, dd.Monitor{Id: dd.Int(id)})
+		}
+		json.NewEncoder(w).Encode(monitors)
+	}))
+	defer ts.Close()
+
+	client := dd.NewClient("foo", "bar")
+	client.SetBaseUrl(ts.URL)
+
+	for _, pageSize := range []int{3, 7} {
+		t.Run(fmt.Sprintf("Page size %d", pageSize), func(t *testing.T) {
+			queries = nil
+			var ids []int
+			pager := client.NewMonitorPager(dd.MonitorQueryOpts{MonitorTags: []string{"team:web"}}, pageSize)
+			for pager.Next() {
+				for _, monitor := range pager.Monitors() {
+					ids = append(ids, monitor.GetId())
+				}
+			}
+			assert.Nil(t, pager.Err())
+			assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6}, ids)
+			assert.Equal(t, fmt.Sprintf("monitor_tags=team%%3Aweb&page=0&page_size=%d", pageSize), queries[0])
+		})
+	}
+	t.Run("Page sizes that are not positive take the default", func(t *testing.T) {
+		queries = nil
+		pager := client.NewMonitorPager(dd.MonitorQueryOpts{}, 0)
+		assert.True(t, pager.Next())
+		assert.Len(t, pager.Monitors(), total)
+		assert.False(t, pager.Next())
+		assert.Equal(t, []string{fmt.Sprintf("page=0&page_size=%d", dd.DefaultMonitorPageSize)}, queries)
+	})
+	t.Run("Errors stop the pager", func(t *testing.T) {
+		client := dd.NewClientWithOptions(dd.WithBaseUrl("http://127.0.0.1:1"), dd.WithRetryTimeout(time.Millisecond))
+		pager := client.NewMonitorPager(dd.MonitorQueryOpts{}, 10)
+		assert.False(t, pager.Next())
+		assert.NotNil(t, pager.Err())
+		assert.False(t, pager.Next())
+	})
+}
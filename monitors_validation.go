@@ -0,0 +1,142 @@
+/*
+ * Datadog API for Go
+ *
+ * Please see the included LICENSE file for licensing information.
+ *
+ * Copyright 2020 by authors and contributors.
+ */
+
+package datadog
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// MonitorValidationError lists the problems found with a monitor definition,
+// either by CheckMonitor or by the monitor validation endpoint.
+type MonitorValidationError struct {
+	Errors []string
+}
+
+func (e *MonitorValidationError) Error() string {
+	return "invalid monitor: " + strings.Join(e.Errors, "; ")
+}
+
+// ValidateMonitor checks a monitor definition with the monitor validation
+// endpoint, without creating or updating it. CheckMonitor is run first so
+// that obvious mistakes are reported without a round trip. Invalid monitors
+// get a *MonitorValidationError.
+func (client *Client) ValidateMonitor(monitor *Monitor) error {
+	if err := CheckMonitor(monitor); err != nil {
+		return err
+	}
+
+	err := client.doJsonRequest("POST", "/v1/monitor/validate", monitor, nil)
+	if apiErr, ok := err.(*APIError); ok && apiErr.StatusCode == http.StatusBadRequest && len(apiErr.Errors) > 0 {
+		return &MonitorValidationError{Errors: apiErr.Errors}
+	}
+	return err
+}
+
+// CheckMonitor looks for mistakes in a monitor definition without calling
+// the API: a query that does not fit the monitor type, a threshold that does
+// not match the query, critical and warning thresholds in the wrong order, or
+// threshold windows missing for an anomaly query. It returns a
+// *MonitorValidationError listing all of them, or nil. Monitor types it does
+// not know are only checked for a query.
+func CheckMonitor(monitor *Monitor) error {
+	var errs []string
+	if monitor == nil {
+		return &MonitorValidationError{Errors: []string{"no monitor specified"}}
+	}
+
+	query := strings.TrimSpace(monitor.GetQuery())
+	if query == "" {
+		errs = append(errs, "query is required")
+	}
+
+	switch monitor.GetType() {
+	case "":
+		errs = append(errs, "type is required")
+	case "metric alert", "query alert":
+		errs = append(errs, checkThresholdQuery(query, monitor.Options)...)
+	case "log alert":
+		if !strings.HasPrefix(query, "logs(") {
+			errs = append(errs, `log alert query must start with "logs("`)
+		}
+		errs = append(errs, checkThresholdQuery(query, monitor.Options)...)
+	case "service check":
+		if _, err := ParseServiceCheckQuery(query); err != nil {
+			errs = append(errs, `service check query must look like "check".over(tags).last(count).count_by_status()`)
+		}
+	case "composite":
+		if _, err := ParseCompositeQuery(query); err != nil {
+			errs = append(errs, "composite query must only combine monitor IDs with &&, || and !")
+		}
+	}
+
+	if strings.Contains(query, "anomalies(") {
+		windows := monitor.GetOptions().ThresholdWindows
+		if windows.GetTriggerWindow() == "" || windows.GetRecoveryWindow() == "" {
+			errs = append(errs, "anomaly queries require trigger and recovery threshold windows")
+		}
+	}
+
+	if len(errs) > 0 {
+		return &MonitorValidationError{Errors: errs}
+	}
+	return nil
+}
+
+// checkThresholdQuery checks that query ends with a comparison, that its
+// threshold is the critical one of options, and that the other thresholds are
+// on the right side of it.
+func checkThresholdQuery(query string, options *Options) []string {
+	_, comparator, threshold, ok := splitQueryComparison(query)
+	if !ok {
+		return []string{"query must end with a comparison to the critical threshold, e.g. > 90"}
+	}
+	// The comparison only matches valid numbers.
+	critical, _ := threshold.Float64()
+
+	var errs []string
+	thresholds := options.GetThresholds()
+	if value, ok := thresholdValue(thresholds.Critical); ok && value != critical {
+		errs = append(errs, fmt.Sprintf("critical threshold %v does not match the threshold %v of the query", value, critical))
+	}
+
+	// above is true when the monitor alerts on values above the threshold,
+	// in which case the other thresholds must be below the critical one.
+	above := comparator == ">" || comparator == ">="
+	ordered := func(name string, value, limit float64, limitName string) {
+		if (above && value >= limit) || (!above && value <= limit) {
+			side := "below"
+			if !above {
+				side = "above"
+			}
+			errs = append(errs, fmt.Sprintf("%s threshold %v must be %s the %s threshold %v", name, value, side, limitName, limit))
+		}
+	}
+	if warning, ok := thresholdValue(thresholds.Warning); ok {
+		ordered("warning", warning, critical, "critical")
+		if recovery, ok := thresholdValue(thresholds.WarningRecovery); ok {
+			ordered("warning recovery", recovery, warning, "warning")
+		}
+	}
+	if recovery, ok := thresholdValue(thresholds.CriticalRecovery); ok {
+		ordered("critical recovery", recovery, critical, "critical")
+	}
+	return errs
+}
+
+// thresholdValue returns the value of a threshold, if it is set to a number.
+func thresholdValue(n *json.Number) (float64, bool) {
+	if n == nil {
+		return 0, false
+	}
+	value, err := n.Float64()
+	return value, err == nil
+}
@@ -0,0 +1,209 @@
+package datadog_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	dd "github.com/zorkian/go-datadog-api"
+)
+
+func thresholds(critical, warning string) *dd.Options {
+	t := &dd.ThresholdCount{Critical: dd.JsonNumber(json.Number(critical))}
+	if warning != "" {
+		t.Warning = dd.JsonNumber(json.Number(warning))
+	}
+	return &dd.Options{Thresholds: t}
+}
+
+func TestCheckMonitor(t *testing.T) {
+	tests := []struct {
+		desc    string
+		monitor dd.Monitor
+		errors  []string
+	}{
+		{
+			"valid metric alert",
+			dd.Monitor{
+				Type:    dd.String("metric alert"),
+				Query:   dd.String("avg(last_5m):sum:system.net.bytes_rcvd{host:host0} > 100"),
+				Options: thresholds("100", "80"),
+			},
+			nil,
+		},
+		{
+			"valid query alert below threshold",
+			dd.Monitor{
+				Type:    dd.String("query alert"),
+				Query:   dd.String("avg(last_5m):avg:system.mem.pct_usable{*} by {host} <= 0.1"),
+				Options: thresholds("0.1", "0.2"),
+			},
+			nil,
+		},
+		{
+			"missing type and query",
+			dd.Monitor{},
+			[]string{"query is required", "type is required"},
+		},
+		{
+			"missing comparison",
+			dd.Monitor{Type: dd.String("metric alert"), Query: dd.String("avg(last_5m):sum:foo{*}")},
+			[]string{"query must end with a comparison to the critical threshold, e.g. > 90"},
+		},
+		{
+			"critical threshold does not match the query",
+			dd.Monitor{
+				Type:    dd.String("metric alert"),
+				Query:   dd.String("avg(last_5m):sum:foo{*} > 100"),
+				Options: thresholds("90", ""),
+			},
+			[]string{"critical threshold 90 does not match the threshold 100 of the query"},
+		},
+		{
+			"warning above critical",
+			dd.Monitor{
+				Type:    dd.String("metric alert"),
+				Query:   dd.String("avg(last_5m):sum:foo{*} > 100"),
+				Options: thresholds("100", "120"),
+			},
+			[]string{"warning threshold 120 must be below the critical threshold 100"},
+		},
+		{
+			"warning below critical on a below comparison",
+			dd.Monitor{
+				Type:    dd.String("query alert"),
+				Query:   dd.String("avg(last_5m):sum:foo{*} < 10"),
+				Options: thresholds("10", "5"),
+			},
+			[]string{"warning threshold 5 must be above the critical threshold 10"},
+		},
+		{
+			"critical recovery past critical",
+			dd.Monitor{
+				Type:  dd.String("metric alert"),
+				Query: dd.String("avg(last_5m):sum:foo{*} > 100"),
+				Options: &dd.Options{Thresholds: &dd.ThresholdCount{
+					Critical:         dd.JsonNumber("100"),
+					CriticalRecovery: dd.JsonNumber("110"),
+				}},
+			},
+			[]string{"critical recovery threshold 110 must be below the critical threshold 100"},
+		},
+		{
+			"valid log alert",
+			dd.Monitor{
+				Type:  dd.String("log alert"),
+				Query: dd.String(`logs("env:develop").index("main").rollup("count").last("5m") > 500`),
+			},
+			nil,
+		},
+		{
+			"log alert without logs query",
+			dd.Monitor{Type: dd.String("log alert"), Query: dd.String(`avg(last_5m):sum:foo{*} > 100`)},
+			[]string{`log alert query must start with "logs("`},
+		},
+		{
+			"valid service check",
+			dd.Monitor{
+				Type:  dd.String("service check"),
+				Query: dd.String(`"datadog.agent.up".over("*").by("host").last(2).count_by_status()`),
+			},
+			nil,
+		},
+		{
+			"malformed service check",
+			dd.Monitor{Type: dd.String("service check"), Query: dd.String(`"datadog.agent.up".last(2)`)},
+			[]string{`service check query must look like "check".over(tags).last(count).count_by_status()`},
+		},
+		{
+			"valid composite",
+			dd.Monitor{Type: dd.String("composite"), Query: dd.String("(12 && 34) || !56")},
+			nil,
+		},
+		{
+			"malformed composite",
+			dd.Monitor{Type: dd.String("composite"), Query: dd.String("12 && avg(last_5m):sum:foo{*} > 1")},
+			[]string{"composite query must only combine monitor IDs with &&, || and !"},
+		},
+		{
+			"dangling composite operator",
+			dd.Monitor{Type: dd.String("composite"), Query: dd.String("(12 && 34) ||")},
+			[]string{"composite query must only combine monitor IDs with &&, || and !"},
+		},
+		{
+			"anomaly query without threshold windows",
+			dd.Monitor{
+				Type:  dd.String("query alert"),
+				Query: dd.String("avg(last_4h):anomalies(avg:foo{*}, 'basic', 2) >= 1"),
+			},
+			[]string{"anomaly queries require trigger and recovery threshold windows"},
+		},
+		{
+			"anomaly query with threshold windows",
+			dd.Monitor{
+				Type:  dd.String("query alert"),
+				Query: dd.String("avg(last_4h):anomalies(avg:foo{*}, 'basic', 2) >= 1"),
+				Options: &dd.Options{ThresholdWindows: &dd.ThresholdWindows{
+					TriggerWindow:  dd.String("last_15m"),
+					RecoveryWindow: dd.String("last_15m"),
+				}},
+			},
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			err := dd.CheckMonitor(&tt.monitor)
+			if tt.errors == nil {
+				assert.Nil(t, err)
+				return
+			}
+			if assert.IsType(t, &dd.MonitorValidationError{}, err) {
+				assert.Equal(t, tt.errors, err.(*dd.MonitorValidationError).Errors)
+			}
+		})
+	}
+}
+
+func TestValidateMonitor(t *testing.T) {
+	var calls int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/api/v1/monitor/validate", r.URL.Path)
+		body, _ := ioutil.ReadAll(r.Body)
+		var monitor dd.Monitor
+		json.Unmarshal(body, &monitor)
+		if monitor.GetName() == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"errors": ["Name is required"]}`))
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer ts.Close()
+
+	client := dd.NewClient("foo", "bar")
+	client.SetBaseUrl(ts.URL)
+	monitor := dd.Monitor{
+		Type:  dd.String("metric alert"),
+		Query: dd.String("avg(last_5m):sum:foo{*} > 100"),
+	}
+
+	err := client.ValidateMonitor(&monitor)
+	if assert.IsType(t, &dd.MonitorValidationError{}, err) {
+		assert.Equal(t, []string{"Name is required"}, err.(*dd.MonitorValidationError).Errors)
+	}
+
+	monitor.SetName("foo")
+	assert.Nil(t, client.ValidateMonitor(&monitor))
+	assert.Equal(t, 2, calls)
+
+	// Local checks fail before any request is sent.
+	monitor.SetQuery("avg(last_5m):sum:foo{*}")
+	assert.NotNil(t, client.ValidateMonitor(&monitor))
+	assert.Equal(t, 2, calls)
+}
@@ -4,8 +4,46 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
+	"time"
 )
 
+// RateLimitMode tells the client what to do with requests to an endpoint
+// whose rate limit is used up.
+type RateLimitMode int
+
+const (
+	// RateLimitOff sends requests regardless of the rate limits, letting the
+	// API answer with a 429 status code.
+	RateLimitOff RateLimitMode = iota
+	// RateLimitWait blocks requests until the rate limit resets, or until
+	// the context of the client is done.
+	RateLimitWait
+	// RateLimitFail returns a *RateLimitExceededError right away.
+	RateLimitFail
+)
+
+// RateLimitExceededError is returned instead of sending a request when the
+// rate limit of its endpoint is used up and the client is set to
+// RateLimitFail.
+type RateLimitExceededError struct {
+	Path  string
+	Reset time.Time
+}
+
+func (e *RateLimitExceededError) Error() string {
+	return fmt.Sprintf("rate limit exceeded for %s, resets at %s", e.Path, e.Reset.Format(time.RFC3339))
+}
+
+// rateLimitWindow is the number of requests left for an endpoint until its
+// rate limit resets.
+type rateLimitWindow struct {
+	limit     int
+	remaining int
+	period    time.Duration
+	reset     time.Time
+}
+
 // The list of Rate Limited Endpoints of the Datadog API.
 // https://docs.datadoghq.com/api/?lang=bash#rate-limiting
 func (client *Client) updateRateLimits(resp *http.Response, api *url.URL) error {
@@ -16,24 +54,100 @@ func (client *Client) updateRateLimits(resp *http.Response, api *url.URL) error
 		// The endpoint is not Rate Limited.
 		return nil
 	}
-	client.m.Lock()
-	defer client.m.Unlock()
-	client.rateLimitingStats[api.Path] = RateLimit{
+	root := client.root()
+	root.m.Lock()
+	defer root.m.Unlock()
+	rateLimit := RateLimit{
 		Limit:     resp.Header.Get("X-RateLimit-Limit"),
 		Reset:     resp.Header.Get("X-RateLimit-Reset"),
 		Period:    resp.Header.Get("X-RateLimit-Period"),
 		Remaining: resp.Header.Get("X-RateLimit-Remaining"),
 	}
+	root.rateLimitingStats[api.Path] = rateLimit
+
+	if window, ok := newRateLimitWindow(rateLimit, time.Now()); ok {
+		if root.rateLimitWindows == nil {
+			root.rateLimitWindows = make(map[string]*rateLimitWindow)
+		}
+		root.rateLimitWindows[api.Path] = window
+	}
 	return nil
 }
 
+// newRateLimitWindow parses the rate limit headers received at now. Reset is
+// the number of seconds until the limit resets; if it is missing, a whole
+// Period is assumed.
+func newRateLimitWindow(rateLimit RateLimit, now time.Time) (*rateLimitWindow, bool) {
+	remaining, err := strconv.Atoi(rateLimit.Remaining)
+	if err != nil {
+		return nil, false
+	}
+	// Limit and Period are only needed to refill the window once it resets.
+	limit, _ := strconv.Atoi(rateLimit.Limit)
+	period, _ := strconv.Atoi(rateLimit.Period)
+	reset, err := strconv.Atoi(rateLimit.Reset)
+	if err != nil {
+		reset = period
+	}
+	return &rateLimitWindow{
+		limit:     limit,
+		remaining: remaining,
+		period:    time.Duration(period) * time.Second,
+		reset:     now.Add(time.Duration(reset) * time.Second),
+	}, true
+}
+
+// throttle takes one request out of the rate limit of req's endpoint. When
+// there is none left it waits for the limit to reset or fails, depending on
+// the RateLimiting mode of the client.
+func (client *Client) throttle(req *http.Request) error {
+	if client.RateLimiting == RateLimitOff {
+		return nil
+	}
+	root := client.root()
+	for {
+		root.m.Lock()
+		window := root.rateLimitWindows[req.URL.Path]
+		if window == nil {
+			// Nothing is known about this endpoint yet.
+			root.m.Unlock()
+			return nil
+		}
+		now := time.Now()
+		if !now.Before(window.reset) && window.limit > 0 && window.period > 0 {
+			window.remaining = window.limit
+			window.reset = now.Add(window.period)
+		}
+		if window.remaining > 0 || !now.Before(window.reset) {
+			window.remaining--
+			root.m.Unlock()
+			return nil
+		}
+		reset := window.reset
+		root.m.Unlock()
+
+		if client.RateLimiting == RateLimitFail {
+			return &RateLimitExceededError{Path: req.URL.Path, Reset: reset}
+		}
+
+		timer := time.NewTimer(reset.Sub(now))
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
 // GetRateLimitStats is a threadsafe getter to retrieve the rate limiting stats associated with the Client.
 func (client *Client) GetRateLimitStats() map[string]RateLimit {
-	client.m.Lock()
-	defer client.m.Unlock()
+	root := client.root()
+	root.m.Lock()
+	defer root.m.Unlock()
 	// Shallow copy to avoid corrupted data
-	mapCopy := make(map[string]RateLimit, len(client.rateLimitingStats))
-	for k, v := range client.rateLimitingStats {
+	mapCopy := make(map[string]RateLimit, len(root.rateLimitingStats))
+	for k, v := range root.rateLimitingStats {
 		mapCopy[k] = v
 	}
 	return mapCopy
@@ -1,11 +1,14 @@
 package datadog
 
 import (
+	"context"
 	"fmt"
 	"github.com/stretchr/testify/assert"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"testing"
+	"time"
 )
 
 func Test_updateRateLimits(t *testing.T) {
@@ -89,3 +92,73 @@ func makeEmptyHeader() *http.Response {
 		Header: nil,
 	}
 }
+
+func TestThrottle(t *testing.T) {
+	makeClient := func(mode RateLimitMode, window rateLimitWindow) *Client {
+		return &Client{
+			RateLimiting:     mode,
+			rateLimitWindows: map[string]*rateLimitWindow{"/api/v1/query": &window},
+		}
+	}
+	req, _ := http.NewRequest("GET", "https://base.datadoghq.com/api/v1/query", nil)
+
+	t.Run("Off never throttles", func(t *testing.T) {
+		client := makeClient(RateLimitOff, rateLimitWindow{reset: time.Now().Add(time.Hour)})
+		assert.Nil(t, client.throttle(req))
+	})
+	t.Run("Unknown endpoints are not throttled", func(t *testing.T) {
+		client := makeClient(RateLimitFail, rateLimitWindow{reset: time.Now().Add(time.Hour)})
+		other, _ := http.NewRequest("GET", "https://base.datadoghq.com/api/v1/monitor", nil)
+		assert.Nil(t, client.throttle(other))
+	})
+	t.Run("Fail returns an error once the limit is used up", func(t *testing.T) {
+		reset := time.Now().Add(time.Hour)
+		client := makeClient(RateLimitFail, rateLimitWindow{remaining: 1, reset: reset})
+		assert.Nil(t, client.throttle(req))
+		err := client.throttle(req)
+		assert.Equal(t, &RateLimitExceededError{Path: "/api/v1/query", Reset: reset}, err)
+		assert.True(t, IsRateLimited(err))
+	})
+	t.Run("Wait blocks until the limit resets", func(t *testing.T) {
+		client := makeClient(RateLimitWait, rateLimitWindow{
+			limit:  2,
+			period: time.Hour,
+			reset:  time.Now().Add(50 * time.Millisecond),
+		})
+		start := time.Now()
+		assert.Nil(t, client.throttle(req))
+		assert.True(t, time.Since(start) >= 50*time.Millisecond)
+		assert.Equal(t, 1, client.rateLimitWindows["/api/v1/query"].remaining)
+	})
+	t.Run("Wait gives up when the context is done", func(t *testing.T) {
+		client := makeClient(RateLimitWait, rateLimitWindow{reset: time.Now().Add(time.Hour)})
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		assert.Equal(t, context.Canceled, client.throttle(req.WithContext(ctx)))
+	})
+	t.Run("Requests are not sent once the limit is used up", func(t *testing.T) {
+		calls := 0
+		mux := http.NewServeMux()
+		mux.HandleFunc("/api/v1/query", func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.Header().Set("X-RateLimit-Limit", "1")
+			w.Header().Set("X-RateLimit-Period", "3600")
+			w.Header().Set("X-RateLimit-Reset", "3600")
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Write([]byte(`{}`))
+		})
+		s := httptest.NewServer(mux)
+		defer s.Close()
+		client := NewClient("sample_api_key", "sample_app_key")
+		client.SetBaseUrl(s.URL)
+		client.RateLimiting = RateLimitFail
+
+		for _, method := range []string{"GET", "POST"} {
+			assert.Nil(t, client.doJsonRequest(method, "/v1/query", nil, nil))
+			err := client.doJsonRequest(method, "/v1/query", nil, nil)
+			assert.IsType(t, &RateLimitExceededError{}, err)
+			client.rateLimitWindows = nil
+		}
+		assert.Equal(t, 2, calls)
+	})
+}
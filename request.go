@@ -16,10 +16,9 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"reflect"
 	"strings"
 	"time"
-
-	"github.com/cenkalti/backoff"
 )
 
 // Response contains common fields that might be present in any API response.
@@ -28,8 +27,15 @@ type Response struct {
 	Error  string `json:"error"`
 }
 
+// legacyKeysInQuery are the endpoints that historically got their keys in the
+// query string. They accept keys in headers as well nowadays.
+var legacyKeysInQuery = []string{"/v1/series", "/v1/check_run", "/v1/events", "/v1/screen"}
+
 func (client *Client) apiAcceptsKeysInHeaders(api string) bool {
-	for _, prefix := range []string{"/v1/series", "/v1/check_run", "/v1/events", "/v1/screen"} {
+	if client.AuthMode != AuthModeLegacy {
+		return true
+	}
+	for _, prefix := range legacyKeysInQuery {
 		if strings.HasPrefix(api, prefix) {
 			return false
 		}
@@ -40,7 +46,8 @@ func (client *Client) apiAcceptsKeysInHeaders(api string) bool {
 // uriForAPI is to be called with either an API resource like "/v1/events"
 // or a full URL like the IP Ranges one
 // and it will give the proper request URI to be posted to.
-func (client *Client) uriForAPI(api string) (string, error) {
+// Keys are put in the query string of the endpoints that need them.
+func (client *Client) uriForAPI(api string, keys Credentials) (string, error) {
 	var err error
 	// If api is a URI such as /v1/hosts/, /v2/dashboards... add credentials and return a properly formatted URL
 	if !(strings.HasPrefix(api, "https://") || strings.HasPrefix(api, "http://")) {
@@ -50,87 +57,119 @@ func (client *Client) uriForAPI(api string) (string, error) {
 		}
 		q := apiBase.Query()
 		if !client.apiAcceptsKeysInHeaders(api) {
-			q.Add("api_key", client.apiKey)
-			q.Add("application_key", client.appKey)
+			q.Add("api_key", keys.APIKey)
+			q.Add("application_key", keys.AppKey)
 		}
 		apiBase.RawQuery = q.Encode()
-		return apiBase.String(), nil
+		return apiBase.String(), client.checkNoKeysInURL(apiBase)
 	}
 	// if api is a generic URL we simply return it
 	apiBase, err := url.Parse(api)
 	if err != nil {
 		return "", err
 	}
-	return apiBase.String(), nil
+	return apiBase.String(), client.checkNoKeysInURL(apiBase)
+}
+
+// checkNoKeysInURL fails if the client is in AuthModeStrict and u holds keys
+// in its query string.
+func (client *Client) checkNoKeysInURL(u *url.URL) error {
+	if client.AuthMode != AuthModeStrict {
+		return nil
+	}
+	q := u.Query()
+	for _, param := range []string{"api_key", "application_key"} {
+		if _, ok := q[param]; ok {
+			return fmt.Errorf("refusing to send %s in the URL of %s", param, u.Path)
+		}
+	}
+	return nil
 }
 
 // URLIPRanges returns the IP Ranges URL used to whitelist IP addresses in use to send data to Datadog
 // agents, api, apm, logs, process, synthetics, webhooks
 func (client *Client) URLIPRanges() (string, error) {
-	baseURL := client.GetBaseUrl()
-	// Get the domain from the URL: eu, com...
-	domain := strings.Split(baseURL, ".")[2]
-	var urlIPRanges string
-	switch domain {
-	case "eu":
-		urlIPRanges = "https://ip-ranges.datadoghq.eu"
-	case "com":
-		urlIPRanges = "https://ip-ranges.datadoghq.com"
-	}
-	return urlIPRanges, nil
+	site, err := client.GetSite()
+	if err != nil {
+		return "", err
+	}
+	return site.IPRangesURL, nil
 }
 
 // redactError removes api and application keys from error strings
-func (client *Client) redactError(err error) error {
+func (keys Credentials) redactError(err error) error {
 	if err == nil {
 		return nil
 	}
-	errString := err.Error()
-
-	if len(client.apiKey) > 0 {
-		errString = strings.Replace(errString, client.apiKey, "redacted", -1)
-	}
-	if len(client.appKey) > 0 {
-		errString = strings.Replace(errString, client.appKey, "redacted", -1)
-	}
+	errString := keys.redactString(err.Error())
 
 	// Return original error if no replacements were made to keep the original,
 	// probably more useful error type information.
 	if errString == err.Error() {
 		return err
 	}
+
+	// Keep API errors typed so callers can still inspect them.
+	if apiErr, ok := err.(*APIError); ok {
+		redacted := *apiErr
+		redacted.Path = keys.redactString(apiErr.Path)
+		redacted.Body = keys.redactString(apiErr.Body)
+		redacted.Errors = make([]string, len(apiErr.Errors))
+		for i, e := range apiErr.Errors {
+			redacted.Errors[i] = keys.redactString(e)
+		}
+		return &redacted
+	}
 	return fmt.Errorf("%s", errString)
 }
 
+// redactString replaces api and application keys in s.
+func (keys Credentials) redactString(s string) string {
+	if len(keys.APIKey) > 0 {
+		s = strings.Replace(s, keys.APIKey, "redacted", -1)
+	}
+	if len(keys.AppKey) > 0 {
+		s = strings.Replace(s, keys.AppKey, "redacted", -1)
+	}
+	return s
+}
+
 // doJsonRequest is the simplest type of request: a method on a URI that
 // returns some JSON result which we unmarshal into the passed interface. It
-// wraps doJsonRequestUnredacted to redact api and application keys from
-// errors.
+// wraps doJsonRequestUnredacted to redact the api and application keys the
+// request was sent with from errors.
 func (client *Client) doJsonRequest(method, api string,
 	reqbody, out interface{}) error {
-	if err := client.doJsonRequestUnredacted(method, api, reqbody, out); err != nil {
-		return client.redactError(err)
+	req, keys, err := client.createRequest(method, api, reqbody)
+	if err != nil {
+		return err
+	}
+	if err := client.doJsonRequestUnredacted(req, api, keys, out); err != nil {
+		return keys.redactError(err)
 	}
 	return nil
 }
 
-// doJsonRequestUnredacted is the simplest type of request: a method on a URI that returns
-// some JSON result which we unmarshal into the passed interface.
-func (client *Client) doJsonRequestUnredacted(method, api string,
-	reqbody, out interface{}) error {
-	req, err := client.createRequest(method, api, reqbody)
-	if err != nil {
-		return err
-	}
-	// Perform the request and retry it if it's not a POST or PUT request
+// doJsonRequestUnredacted sends req, made by createRequest for api with keys,
+// and unmarshals the JSON result into the passed interface.
+func (client *Client) doJsonRequestUnredacted(req *http.Request, api string, keys Credentials, out interface{}) error {
+	// Perform the request and retry it if the retry policy allows it
 	var resp *http.Response
-	if method == "POST" || method == "PUT" {
-		resp, err = client.HttpClient.Do(req)
+	var err error
+	if client.retryPolicy().Retryable(req.Method, api) {
+		resp, err = client.doRequestWithRetries(req, keys, client.RetryTimeout)
 	} else {
-		resp, err = client.doRequestWithRetries(req, client.RetryTimeout)
+		resp, err = client.do(req, keys, 1)
 	}
 	if err != nil {
-		return err
+		if resp == nil {
+			return err
+		}
+		if req.Context().Err() != nil {
+			resp.Body.Close()
+			return err
+		}
+		// Retries gave up on a failing status code, which is reported below.
 	}
 	defer resp.Body.Close()
 
@@ -139,7 +178,7 @@ func (client *Client) doJsonRequestUnredacted(method, api string,
 		if err != nil {
 			return err
 		}
-		return fmt.Errorf("API error %s: %s", resp.Status, body)
+		return newAPIError(req, resp, body)
 	}
 
 	body, err := ioutil.ReadAll(resp.Body)
@@ -153,12 +192,6 @@ func (client *Client) doJsonRequestUnredacted(method, api string,
 		body = []byte{'{', '}'}
 	}
 
-	err = client.updateRateLimits(resp, req.URL)
-	if err != nil {
-		// Inability to update the rate limiting stats should not be a blocking error.
-		fmt.Printf("Error Updating the Rate Limit statistics: %s", err.Error())
-	}
-
 	// Try to parse common response fields to check whether there's an error reported in a response.
 	var common *Response
 	err = json.Unmarshal(body, &common)
@@ -180,20 +213,31 @@ func (client *Client) doJsonRequestUnredacted(method, api string,
 		return nil
 	}
 
-	return json.Unmarshal(body, &out)
+	if err := json.Unmarshal(body, &out); err != nil {
+		return err
+	}
+	if client.Lossless {
+		return keepUnknownFields(reflect.ValueOf(out), body)
+	}
+	return nil
 }
 
-// doRequestWithRetries performs an HTTP request repeatedly for maxTime or until
-// no error and no acceptable HTTP response code was returned.
-func (client *Client) doRequestWithRetries(req *http.Request, maxTime time.Duration) (*http.Response, error) {
+// doRequestWithRetries performs an HTTP request repeatedly for maxTime, or with
+// no time limit if it is 0, until no error and no acceptable HTTP response
+// code was returned. Retries stop
+// early when the request's context is done. Rate limited requests are retried
+// after the delay advertised by the API, or the backoff of the retry policy if
+// that is longer, as long as the wait fits in maxTime. The wait is logged, and
+// reported in the APIError returned if the request still fails.
+func (client *Client) doRequestWithRetries(req *http.Request, keys Credentials, maxTime time.Duration) (*http.Response, error) {
 	var (
 		err  error
 		resp *http.Response
-		bo   = backoff.NewExponentialBackOff()
+		bo   = &policyBackOff{policy: client.retryPolicy(), maxTime: maxTime}
 		body []byte
-	)
 
-	bo.MaxElapsedTime = maxTime
+		attempt int
+	)
 
 	// Save the body for retries
 	if req.Body != nil {
@@ -209,14 +253,31 @@ func (client *Client) doRequestWithRetries(req *http.Request, maxTime time.Durat
 			req.Body = ioutil.NopCloser(r)
 		}
 
-		resp, err = client.HttpClient.Do(req)
+		// Discard the response of the previous attempt
+		if resp != nil {
+			resp.Body.Close()
+		}
+
+		attempt++
+		resp, err = client.do(req, keys, attempt)
 		if err != nil {
+			if _, ok := err.(*RateLimitExceededError); ok {
+				return &permanentError{err}
+			}
 			return err
 		}
 
 		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
 			// 2xx all done
 			return nil
+		} else if resp.StatusCode == http.StatusTooManyRequests {
+			// 429 are retried once the rate limit resets, as long as that
+			// happens within maxTime.
+			delay, ok := retryAfter(resp, time.Now())
+			if !ok {
+				return nil
+			}
+			return &retryAfterError{delay: delay}
 		} else if resp.StatusCode >= 400 && resp.StatusCode < 500 {
 			// 4xx are not retryable
 			return nil
@@ -225,23 +286,75 @@ func (client *Client) doRequestWithRetries(req *http.Request, maxTime time.Durat
 		return fmt.Errorf("Received HTTP status code %d", resp.StatusCode)
 	}
 
-	err = backoff.Retry(operation, bo)
+	err = retryWithContext(req.Context(), operation, bo, func(err error, wait time.Duration) {
+		if _, ok := err.(*retryAfterError); ok {
+			client.logRateLimited(req, attempt, wait)
+		}
+	})
 
 	return resp, err
 }
 
-func (client *Client) createRequest(method, api string, reqbody interface{}) (*http.Request, error) {
+// do sends a single request through the middlewares and the HTTP client, and
+// records the rate limits reported back. If the client throttles itself, it
+// first waits for the rate limit of the request's endpoint. attempt counts the
+// times the request was sent, for logging.
+func (client *Client) do(req *http.Request, keys Credentials, attempt int) (*http.Response, error) {
+	if err := client.throttle(req); err != nil {
+		return nil, err
+	}
+
+	client.dumpRequest(req, keys)
+	start := time.Now()
+	resp, err := client.roundTrip(req)
+	client.logRequest(req, keys, resp, err, attempt, time.Since(start))
+	if err != nil {
+		return nil, err
+	}
+	client.dumpResponse(resp, keys)
+
+	err = client.updateRateLimits(resp, req.URL)
+	if err != nil && client.Logger != nil {
+		// Inability to update the rate limiting stats should not be a blocking error.
+		client.Logger.Warn("failed to update the rate limit statistics", "path", req.URL.Path, "error", err.Error())
+	}
+	return resp, nil
+}
+
+// createRequest builds a request to api with the keys the client currently
+// has, and returns those keys so that the errors, logs and dumps of the
+// request are redacted with the keys it was actually sent with. Its own
+// errors are redacted already.
+func (client *Client) createRequest(method, api string, reqbody interface{}) (*http.Request, Credentials, error) {
+	keys, err := client.keys()
+	if err != nil {
+		return nil, Credentials{}, err
+	}
+	req, err := client.createRequestWithKeys(method, api, reqbody, keys)
+	if err != nil {
+		return nil, Credentials{}, keys.redactError(err)
+	}
+	return req, keys, nil
+}
+
+func (client *Client) createRequestWithKeys(method, api string, reqbody interface{}, keys Credentials) (*http.Request, error) {
 	// Handle the body if they gave us one.
 	var bodyReader io.Reader
+	var compression Compression
 	if method != "GET" && reqbody != nil {
 		bjson, err := json.Marshal(reqbody)
 		if err != nil {
 			return nil, err
 		}
+		// Compressed bodies are replayed as they are by retries.
+		compression = client.compression(api)
+		if bjson, err = compress(bjson, compression); err != nil {
+			return nil, err
+		}
 		bodyReader = bytes.NewReader(bjson)
 	}
 
-	apiUrlStr, err := client.uriForAPI(api)
+	apiUrlStr, err := client.uriForAPI(api, keys)
 	if err != nil {
 		return nil, err
 	}
@@ -249,13 +362,17 @@ func (client *Client) createRequest(method, api string, reqbody interface{}) (*h
 	if err != nil {
 		return nil, err
 	}
+	req = req.WithContext(client.Context())
 	if client.apiAcceptsKeysInHeaders(api) {
-		req.Header.Set("DD-API-KEY", client.apiKey)
-		req.Header.Set("DD-APPLICATION-KEY", client.appKey)
+		req.Header.Set("DD-API-KEY", keys.APIKey)
+		req.Header.Set("DD-APPLICATION-KEY", keys.AppKey)
 	}
 	if bodyReader != nil {
 		req.Header.Add("Content-Type", "application/json")
 	}
+	if compression != CompressionNone {
+		req.Header.Set("Content-Encoding", string(compression))
+	}
 	for k, v := range client.ExtraHeader {
 		req.Header.Add(k, v)
 	}
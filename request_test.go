@@ -1,17 +1,21 @@
 package datadog
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
 
 var needKeysInQueryParams = []string{"/v1/series", "/v1/check_run", "/v1/events", "/v1/screen"}
 
+var sampleKeys = Credentials{APIKey: "sample_api_key", AppKey: "sample_app_key"}
+
 func TestUriForApi(t *testing.T) {
 	c := Client{
 		apiKey:       "sample_api_key",
@@ -21,19 +25,19 @@ func TestUriForApi(t *testing.T) {
 		RetryTimeout: 1000,
 	}
 	t.Run("Get Uri for api string with query string", func(t *testing.T) {
-		uri, err := c.uriForAPI("/v1/events?type=critical")
+		uri, err := c.uriForAPI("/v1/events?type=critical", sampleKeys)
 		assert.Nil(t, err)
 		assert.Equal(t, "https://base.datadoghq.com/api/v1/events?api_key=sample_api_key&application_key=sample_app_key&type=critical", uri)
 
 	})
 	t.Run("Get Uri for api without query string", func(t *testing.T) {
-		uri, err := c.uriForAPI("/v1/events")
+		uri, err := c.uriForAPI("/v1/events", sampleKeys)
 		assert.Nil(t, err)
 		assert.Equal(t, "https://base.datadoghq.com/api/v1/events?api_key=sample_api_key&application_key=sample_app_key", uri)
 	})
 	t.Run("Test all endpoints that need keys in query params", func(t *testing.T) {
 		for _, api := range needKeysInQueryParams {
-			uri, err := c.uriForAPI(api)
+			uri, err := c.uriForAPI(api, sampleKeys)
 			assert.Nil(t, err)
 			parsed, err := url.Parse(uri)
 			assert.Nil(t, err)
@@ -42,12 +46,52 @@ func TestUriForApi(t *testing.T) {
 		}
 	})
 	t.Run("Test an endpoint that doesn't need keys in query params", func(t *testing.T) {
-		uri, err := c.uriForAPI("/v1/dashboard")
+		uri, err := c.uriForAPI("/v1/dashboard", sampleKeys)
 		assert.Nil(t, err)
 		assert.Equal(t, "https://base.datadoghq.com/api/v1/dashboard", uri)
 	})
 }
 
+func TestAuthModes(t *testing.T) {
+	c := Client{
+		apiKey:       "sample_api_key",
+		appKey:       "sample_app_key",
+		baseUrl:      "https://base.datadoghq.com",
+		HttpClient:   &http.Client{},
+		RetryTimeout: 1000,
+	}
+	for _, mode := range []AuthMode{AuthModeHeaders, AuthModeStrict} {
+		c.AuthMode = mode
+		t.Run(fmt.Sprintf("Mode %d sends keys in headers for every endpoint", mode), func(t *testing.T) {
+			for _, api := range needKeysInQueryParams {
+				req, _, err := c.createRequest("POST", api, nil)
+				assert.Nil(t, err)
+				assert.Equal(t, "https://base.datadoghq.com/api"+api, req.URL.String())
+				assert.Equal(t, "sample_api_key", req.Header.Get("DD-API-KEY"))
+				assert.Equal(t, "sample_app_key", req.Header.Get("DD-APPLICATION-KEY"))
+			}
+		})
+	}
+	t.Run("Headers mode keeps keys given by the caller", func(t *testing.T) {
+		c.AuthMode = AuthModeHeaders
+		uri, err := c.uriForAPI("https://example.com/path?api_key=sample_api_key", sampleKeys)
+		assert.Nil(t, err)
+		assert.Equal(t, "https://example.com/path?api_key=sample_api_key", uri)
+	})
+	t.Run("Strict mode refuses keys in URLs", func(t *testing.T) {
+		c.AuthMode = AuthModeStrict
+		for _, api := range []string{
+			"/v1/events?application_key=sample_app_key",
+			"https://example.com/path?api_key=sample_api_key",
+		} {
+			_, err := c.uriForAPI(api, sampleKeys)
+			if assert.NotNil(t, err) {
+				assert.NotContains(t, err.Error(), "sample_")
+			}
+		}
+	})
+}
+
 func TestCreateRequest(t *testing.T) {
 	c := Client{
 		apiKey:       "sample_api_key",
@@ -57,14 +101,14 @@ func TestCreateRequest(t *testing.T) {
 		RetryTimeout: 1000,
 	}
 	t.Run("Test an endpoint that doesn't need keys in query params", func(t *testing.T) {
-		req, err := c.createRequest("GET", "/v1/dashboard", nil)
+		req, _, err := c.createRequest("GET", "/v1/dashboard", nil)
 		assert.Nil(t, err)
 		assert.Equal(t, "sample_api_key", req.Header.Get("DD-API-KEY"))
 		assert.Equal(t, "sample_app_key", req.Header.Get("DD-APPLICATION-KEY"))
 	})
 	t.Run("Test endpoints that need keys in query params", func(t *testing.T) {
 		for _, api := range needKeysInQueryParams {
-			req, err := c.createRequest("GET", api, nil)
+			req, _, err := c.createRequest("GET", api, nil)
 			assert.Nil(t, err)
 			// we make sure that we *don't* have keys in query params, because some endpoints
 			// fail if we send keys both in headers and query params
@@ -75,15 +119,9 @@ func TestCreateRequest(t *testing.T) {
 }
 
 func TestRedactError(t *testing.T) {
-	c := Client{
-		apiKey:       "sample_api_key",
-		appKey:       "sample_app_key",
-		baseUrl:      "https://base.datadoghq.com",
-		HttpClient:   &http.Client{},
-		RetryTimeout: 1000,
-	}
+	c := sampleKeys
 	t.Run("Error containing api key in string is correctly redacted", func(t *testing.T) {
-		var leakErr = fmt.Errorf("Error test: %s,%s", c.apiKey, c.apiKey)
+		var leakErr = fmt.Errorf("Error test: %s,%s", c.APIKey, c.APIKey)
 		var redactedErr = c.redactError(leakErr)
 
 		if assert.NotNil(t, redactedErr) {
@@ -91,13 +129,29 @@ func TestRedactError(t *testing.T) {
 		}
 	})
 	t.Run("Error containing application key in string is correctly redacted", func(t *testing.T) {
-		var leakErr = fmt.Errorf("Error test: %s,%s", c.appKey, c.appKey)
+		var leakErr = fmt.Errorf("Error test: %s,%s", c.AppKey, c.AppKey)
 		var redactedErr = c.redactError(leakErr)
 
 		if assert.NotNil(t, redactedErr) {
 			assert.Equal(t, "Error test: redacted,redacted", redactedErr.Error())
 		}
 	})
+	t.Run("API error keeps its type once redacted", func(t *testing.T) {
+		var leakErr = &APIError{
+			StatusCode: 403,
+			Status:     "403 Forbidden",
+			Errors:     []string{"bad key " + c.APIKey},
+			Body:       `{"errors": ["bad key ` + c.APIKey + `"]}`,
+		}
+		var redactedErr = c.redactError(leakErr)
+
+		if assert.IsType(t, &APIError{}, redactedErr) {
+			assert.Equal(t, []string{"bad key redacted"}, redactedErr.(*APIError).Errors)
+			assert.Equal(t, "API error 403 Forbidden: {\"errors\": [\"bad key redacted\"]}", redactedErr.Error())
+			assert.True(t, IsForbidden(redactedErr))
+		}
+		assert.Contains(t, leakErr.Body, c.APIKey)
+	})
 	t.Run("Nil error returns nil", func(t *testing.T) {
 		var harmlessErr error = nil
 		var redactedErr = c.redactError(harmlessErr)
@@ -132,10 +186,28 @@ func TestErrorHandling(t *testing.T) {
 
 			for _, method := range []string{"GET", "POST", "PUT"} {
 				err := c.doJsonRequest(method, "/v1/something", nil, nil)
-				assert.NotNil(t, err)
+				if assert.IsType(t, &APIError{}, err) {
+					apiErr := err.(*APIError)
+					assert.Equal(t, code, apiErr.StatusCode)
+					assert.Equal(t, method, apiErr.Method)
+					assert.Equal(t, "/api/v1/something", apiErr.Path)
+				}
 			}
 		})
 	}
+	t.Run("Returns typed error with the Datadog errors", func(t *testing.T) {
+		s := makeTestServer(404, `{"errors": ["Monitor not found"]}`)
+		defer s.Close()
+		c.SetBaseUrl(s.URL)
+
+		err := c.doJsonRequest("GET", "/v1/something", nil, nil)
+		if assert.IsType(t, &APIError{}, err) {
+			assert.Equal(t, []string{"Monitor not found"}, err.(*APIError).Errors)
+		}
+		assert.True(t, IsNotFound(err))
+		assert.False(t, IsRateLimited(err))
+		assert.False(t, IsNotFound(fmt.Errorf("not an API error")))
+	})
 	t.Run("Returns error if status is error", func(t *testing.T) {
 		s := makeTestServer(200, `{"status": "error", "error": "something wrong"}`)
 		defer s.Close()
@@ -159,3 +231,168 @@ func TestErrorHandling(t *testing.T) {
 		}
 	})
 }
+
+func TestWithContext(t *testing.T) {
+	t.Run("Cancelled context stops retries", func(t *testing.T) {
+		s := makeTestServer(500, "")
+		defer s.Close()
+		c := NewClient("sample_api_key", "sample_app_key")
+		c.SetBaseUrl(s.URL)
+		c.RetryTimeout = 30 * time.Second
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		err := c.WithContext(ctx).doJsonRequest("GET", "/v1/something", nil, nil)
+		assert.Equal(t, context.DeadlineExceeded, err)
+		assert.True(t, time.Since(start) < 5*time.Second)
+	})
+	t.Run("Requests carry the context", func(t *testing.T) {
+		c := NewClient("sample_api_key", "sample_app_key")
+		ctx := context.WithValue(context.Background(), "key", "value")
+
+		req, _, err := c.WithContext(ctx).createRequest("GET", "/v1/dashboard", nil)
+		assert.Nil(t, err)
+		assert.Equal(t, ctx, req.Context())
+		assert.Equal(t, context.Background(), c.Context())
+	})
+	t.Run("Views share rate limiting stats", func(t *testing.T) {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/api/v1/query", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-RateLimit-Limit", "300")
+			w.Header().Set("X-RateLimit-Period", "3600")
+			w.Header().Set("X-RateLimit-Reset", "10")
+			w.Header().Set("X-RateLimit-Remaining", "299")
+			w.Write([]byte(`{}`))
+		})
+		s := httptest.NewServer(mux)
+		defer s.Close()
+		c := NewClient("sample_api_key", "sample_app_key")
+		c.SetBaseUrl(s.URL)
+
+		view := c.WithContext(context.Background()).WithContext(context.Background())
+		err := view.doJsonRequest("GET", "/v1/query", nil, nil)
+		assert.Nil(t, err)
+		assert.Equal(t, RateLimit{"300", "3600", "10", "299"}, c.GetRateLimitStats()["/api/v1/query"])
+		assert.Equal(t, c.GetRateLimitStats(), view.GetRateLimitStats())
+	})
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Now()
+	for _, tt := range []struct {
+		desc   string
+		header map[string]string
+		delay  time.Duration
+		ok     bool
+	}{
+		{"no header", map[string]string{}, 0, false},
+		{"retry after seconds", map[string]string{"Retry-After": "12"}, 12 * time.Second, true},
+		{"retry after date", map[string]string{"Retry-After": now.Add(time.Minute).UTC().Format(http.TimeFormat)}, time.Minute, true},
+		{"rate limit reset", map[string]string{"X-RateLimit-Reset": "30"}, 30 * time.Second, true},
+		{"retry after wins", map[string]string{"Retry-After": "5", "X-RateLimit-Reset": "30"}, 5 * time.Second, true},
+		{"malformed", map[string]string{"Retry-After": "soon"}, 0, false},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			resp := &http.Response{Header: make(http.Header)}
+			for k, v := range tt.header {
+				resp.Header.Set(k, v)
+			}
+			delay, ok := retryAfter(resp, now)
+			assert.Equal(t, tt.ok, ok)
+			// HTTP dates only have a precision of one second
+			assert.InDelta(t, float64(tt.delay), float64(delay), float64(time.Second))
+		})
+	}
+}
+
+func TestRateLimitedRetries(t *testing.T) {
+	makeServer := func(failures int, retryAfter string) (*httptest.Server, *int) {
+		calls := 0
+		mux := http.NewServeMux()
+		mux.HandleFunc("/api/v1/query", func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			if calls <= failures {
+				w.Header().Set("Retry-After", retryAfter)
+				w.WriteHeader(http.StatusTooManyRequests)
+				w.Write([]byte(`{"errors": ["Rate limit of 300 requests in 3600 seconds reached."]}`))
+				return
+			}
+			w.Write([]byte(`{}`))
+		})
+		return httptest.NewServer(mux), &calls
+	}
+
+	t.Run("Retries once the rate limit resets", func(t *testing.T) {
+		s, calls := makeServer(1, "1")
+		defer s.Close()
+		c := NewClient("sample_api_key", "sample_app_key")
+		c.SetBaseUrl(s.URL)
+
+		start := time.Now()
+		err := c.doJsonRequest("GET", "/v1/query", nil, nil)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, *calls)
+		assert.True(t, time.Since(start) >= time.Second)
+	})
+	t.Run("Logs the wait of retries that succeed", func(t *testing.T) {
+		s, calls := makeServer(1, "1")
+		defer s.Close()
+		logger := &testLogger{}
+		c := NewClientWithOptions(WithBaseUrl(s.URL), WithLogger(logger))
+
+		assert.Nil(t, c.doJsonRequest("GET", "/v1/query", nil, nil))
+		assert.Equal(t, 2, *calls)
+		var waits []interface{}
+		for _, record := range logger.records {
+			if record.msg == "datadog request rate limited" {
+				assert.Equal(t, "info", record.level)
+				assert.Equal(t, 1, record.args["attempt"])
+				waits = append(waits, record.args["wait"])
+			}
+		}
+		if assert.Len(t, waits, 1) {
+			assert.True(t, waits[0].(time.Duration) >= time.Second)
+		}
+	})
+	t.Run("Gives up when the reset is past the retry timeout", func(t *testing.T) {
+		s, calls := makeServer(1, "60")
+		defer s.Close()
+		c := NewClient("sample_api_key", "sample_app_key")
+		c.SetBaseUrl(s.URL)
+		c.RetryTimeout = 10 * time.Second
+
+		err := c.doJsonRequest("GET", "/v1/query", nil, nil)
+		assert.True(t, IsRateLimited(err))
+		if assert.IsType(t, &APIError{}, err) {
+			assert.Equal(t, time.Minute, err.(*APIError).RetryAfter)
+		}
+		assert.Equal(t, 1, *calls)
+	})
+	t.Run("Waits at least the backoff of the policy", func(t *testing.T) {
+		s, calls := makeServer(2, "0")
+		defer s.Close()
+		c := NewClient("sample_api_key", "sample_app_key")
+		c.SetBaseUrl(s.URL)
+		c.RetryPolicy = &DefaultRetryPolicy{InitialInterval: 50 * time.Millisecond, Multiplier: 1}
+
+		start := time.Now()
+		err := c.doJsonRequest("GET", "/v1/query", nil, nil)
+		assert.Nil(t, err)
+		assert.Equal(t, 3, *calls)
+		assert.True(t, time.Since(start) >= 100*time.Millisecond)
+	})
+	t.Run("Gives up when the backoff is past the retry timeout", func(t *testing.T) {
+		s, calls := makeServer(1, "0")
+		defer s.Close()
+		c := NewClient("sample_api_key", "sample_app_key")
+		c.SetBaseUrl(s.URL)
+		c.RetryPolicy = &DefaultRetryPolicy{InitialInterval: time.Minute}
+		c.RetryTimeout = time.Second
+
+		err := c.doJsonRequest("GET", "/v1/query", nil, nil)
+		assert.True(t, IsRateLimited(err))
+		assert.Equal(t, 1, *calls)
+	})
+}
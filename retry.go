@@ -0,0 +1,228 @@
+/*
+ * Datadog API for Go
+ *
+ * Please see the included LICENSE file for licensing information.
+ *
+ * Copyright 2020 by authors and contributors.
+ */
+
+package datadog
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cenkalti/backoff"
+)
+
+// RetryPolicy decides which requests the client retries when they fail, and
+// how long it waits between two attempts. Requests are retried for at most
+// the RetryTimeout of the client, or for as long as it takes if it is 0.
+type RetryPolicy interface {
+	// Retryable returns true if a request with the given method to api, such
+	// as "/v1/series", is safe to send more than once.
+	Retryable(method, api string) bool
+	// Backoff returns how long to wait before the given retry, starting at 1.
+	Backoff(retry int) time.Duration
+}
+
+// retryableSubmissions are the intake endpoints whose POST requests are
+// retried by DefaultRetryPolicy: losing a payload is worse than the rare
+// duplicate point or event.
+var retryableSubmissions = []string{"/v1/series", "/v1/check_run", "/v1/events"}
+
+// DefaultRetryPolicy retries every request but POST and PUT ones, along with
+// submissions of metrics, check runs and events. It waits exponentially longer
+// between attempts. Its zero fields, but RandomizationFactor, take the values
+// of NewDefaultRetryPolicy.
+type DefaultRetryPolicy struct {
+	// InitialInterval is the wait before the first retry.
+	InitialInterval time.Duration
+	// MaxInterval caps the wait between two attempts.
+	MaxInterval time.Duration
+	// Multiplier is the growth of the wait from one retry to the next, at
+	// least 1.
+	Multiplier float64
+	// RandomizationFactor spreads the wait by up to this fraction of it, so
+	// that clients failing together do not retry together.
+	RandomizationFactor float64
+}
+
+// NewDefaultRetryPolicy returns the retry policy used by clients by default.
+func NewDefaultRetryPolicy() *DefaultRetryPolicy {
+	return &DefaultRetryPolicy{
+		InitialInterval:     backoff.DefaultInitialInterval,
+		MaxInterval:         backoff.DefaultMaxInterval,
+		Multiplier:          backoff.DefaultMultiplier,
+		RandomizationFactor: backoff.DefaultRandomizationFactor,
+	}
+}
+
+// Retryable implements RetryPolicy.
+func (p *DefaultRetryPolicy) Retryable(method, api string) bool {
+	if method != "POST" && method != "PUT" {
+		return true
+	}
+	if method == "POST" {
+		for _, prefix := range retryableSubmissions {
+			if strings.HasPrefix(api, prefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Backoff implements RetryPolicy.
+func (p *DefaultRetryPolicy) Backoff(retry int) time.Duration {
+	initial, max, multiplier := p.InitialInterval, p.MaxInterval, p.Multiplier
+	if initial <= 0 {
+		initial = backoff.DefaultInitialInterval
+	}
+	if max <= 0 {
+		max = backoff.DefaultMaxInterval
+	}
+	if multiplier == 0 {
+		multiplier = backoff.DefaultMultiplier
+	}
+	interval := float64(initial) * math.Pow(math.Max(multiplier, 1), float64(retry-1))
+	interval = math.Min(interval, float64(max))
+	delta := p.RandomizationFactor * interval
+	return time.Duration(interval - delta + rand.Float64()*2*delta)
+}
+
+// retryPolicy returns the retry policy of the client, or the default one.
+func (client *Client) retryPolicy() RetryPolicy {
+	if client.RetryPolicy != nil {
+		return client.RetryPolicy
+	}
+	return NewDefaultRetryPolicy()
+}
+
+// policyBackOff adapts a RetryPolicy to backoff.BackOff. It stops once the
+// next wait would end past maxTime, unless maxTime is 0.
+type policyBackOff struct {
+	policy  RetryPolicy
+	maxTime time.Duration
+	start   time.Time
+	retry   int
+}
+
+func (b *policyBackOff) Reset() {
+	b.start = time.Now()
+	b.retry = 0
+}
+
+func (b *policyBackOff) NextBackOff() time.Duration {
+	return b.nextBackOffAtLeast(0)
+}
+
+// nextBackOffAtLeast returns the next wait, lengthened to min if it is
+// shorter, or backoff.Stop if that wait would end past a maxTime other than 0.
+func (b *policyBackOff) nextBackOffAtLeast(min time.Duration) time.Duration {
+	b.retry++
+	next := b.policy.Backoff(b.retry)
+	if next < min {
+		next = min
+	}
+	if b.maxTime > 0 && time.Since(b.start)+next > b.maxTime {
+		return backoff.Stop
+	}
+	return next
+}
+
+// permanentError wraps an error an operation must not be retried after.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+// retryAfterError asks for an operation to be retried after a given delay
+// rather than after the next backoff interval.
+type retryAfterError struct {
+	delay time.Duration
+}
+
+func (e *retryAfterError) Error() string {
+	return fmt.Sprintf("Rate limited, retrying in %s", e.delay)
+}
+
+// retryAfter returns how long the server asked to wait before retrying, from
+// either the Retry-After or the X-RateLimit-Reset header of resp. Both count
+// in seconds, Retry-After may also be an HTTP date.
+func retryAfter(resp *http.Response, now time.Time) (time.Duration, bool) {
+	if value := resp.Header.Get("Retry-After"); value != "" {
+		if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+			return time.Duration(seconds) * time.Second, true
+		}
+		if date, err := http.ParseTime(value); err == nil {
+			if date.Before(now) {
+				return 0, true
+			}
+			return date.Sub(now), true
+		}
+	}
+	if value := resp.Header.Get("X-RateLimit-Reset"); value != "" {
+		if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+			return time.Duration(seconds) * time.Second, true
+		}
+	}
+	return 0, false
+}
+
+// retryWithContext behaves like backoff.Retry but gives up as soon as ctx is
+// done, including while it is waiting between two attempts, or when the
+// operation returns a permanentError. An operation returning a
+// retryAfterError is retried after the delay it asks for, or the next backoff
+// interval if that is longer; if that wait does not fit, the operation is
+// considered done so that its last response is handled as is. notify, if not
+// nil, is told about every error retried and the wait before the retry.
+func retryWithContext(ctx context.Context, operation backoff.Operation, b *policyBackOff, notify backoff.Notify) error {
+	var err error
+	var next time.Duration
+
+	b.Reset()
+	for {
+		if err = operation(); err == nil {
+			return nil
+		}
+		if permanent, ok := err.(*permanentError); ok {
+			return permanent.err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		var min time.Duration
+		retry, rateLimited := err.(*retryAfterError)
+		if rateLimited {
+			min = retry.delay
+		}
+		if next = b.nextBackOffAtLeast(min); next == backoff.Stop {
+			if rateLimited {
+				return nil
+			}
+			return err
+		}
+		if notify != nil {
+			notify(err, next)
+		}
+
+		timer := time.NewTimer(next)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package datadog
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cenkalti/backoff"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultRetryPolicy(t *testing.T) {
+	p := NewDefaultRetryPolicy()
+	t.Run("Retryable requests", func(t *testing.T) {
+		for _, tt := range []struct {
+			method, api string
+			retryable   bool
+		}{
+			{"GET", "/v1/monitor", true},
+			{"DELETE", "/v1/monitor/12", true},
+			{"POST", "/v1/monitor", false},
+			{"PUT", "/v1/monitor/12", false},
+			{"POST", "/v1/series", true},
+			{"POST", "/v1/check_run", true},
+			{"POST", "/v1/events?priority=normal", true},
+			{"PUT", "/v1/series", false},
+		} {
+			assert.Equal(t, tt.retryable, p.Retryable(tt.method, tt.api), "%s %s", tt.method, tt.api)
+		}
+	})
+	t.Run("Backoff grows up to the max interval", func(t *testing.T) {
+		p := &DefaultRetryPolicy{
+			InitialInterval: time.Second,
+			MaxInterval:     5 * time.Second,
+			Multiplier:      2,
+		}
+		assert.Equal(t, time.Second, p.Backoff(1))
+		assert.Equal(t, 2*time.Second, p.Backoff(2))
+		assert.Equal(t, 4*time.Second, p.Backoff(3))
+		assert.Equal(t, 5*time.Second, p.Backoff(4))
+	})
+	t.Run("Zero fields take the default values", func(t *testing.T) {
+		p := &DefaultRetryPolicy{}
+		assert.Equal(t, backoff.DefaultInitialInterval, p.Backoff(1))
+		assert.Equal(t, time.Duration(float64(backoff.DefaultInitialInterval)*backoff.DefaultMultiplier), p.Backoff(2))
+		assert.Equal(t, backoff.DefaultMaxInterval, p.Backoff(100))
+	})
+	t.Run("Backoff is randomized", func(t *testing.T) {
+		p := &DefaultRetryPolicy{InitialInterval: time.Second, RandomizationFactor: 0.5}
+		for i := 0; i < 100; i++ {
+			next := p.Backoff(1)
+			assert.True(t, next >= 500*time.Millisecond && next <= 1500*time.Millisecond)
+		}
+	})
+}
+
+type noRetryPolicy struct{}
+
+func (noRetryPolicy) Retryable(method, api string) bool { return false }
+func (noRetryPolicy) Backoff(retry int) time.Duration   { return 0 }
+
+func TestRetryPolicy(t *testing.T) {
+	makeServer := func() (*httptest.Server, *int) {
+		calls := 0
+		mux := http.NewServeMux()
+		mux.HandleFunc("/api/v1/series", func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			if calls == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Write([]byte(`{"status": "ok"}`))
+		})
+		return httptest.NewServer(mux), &calls
+	}
+
+	t.Run("Metric submissions are retried", func(t *testing.T) {
+		s, calls := makeServer()
+		defer s.Close()
+		c := NewClient("sample_api_key", "sample_app_key")
+		c.SetBaseUrl(s.URL)
+		c.RetryPolicy = &DefaultRetryPolicy{InitialInterval: time.Millisecond}
+
+		err := c.PostMetrics([]Metric{{Metric: String("foo.bar")}})
+		assert.Nil(t, err)
+		assert.Equal(t, 2, *calls)
+	})
+	t.Run("Custom policies can disable retries", func(t *testing.T) {
+		s, calls := makeServer()
+		defer s.Close()
+		c := NewClient("sample_api_key", "sample_app_key")
+		c.SetBaseUrl(s.URL)
+		c.RetryPolicy = noRetryPolicy{}
+
+		err := c.PostMetrics([]Metric{{Metric: String("foo.bar")}})
+		assert.Equal(t, http.StatusServiceUnavailable, statusCode(err))
+		assert.Equal(t, 1, *calls)
+	})
+	t.Run("Requests are retried with no time limit if the timeout is 0", func(t *testing.T) {
+		s, calls := makeServer()
+		defer s.Close()
+		c := NewClient("sample_api_key", "sample_app_key")
+		c.SetBaseUrl(s.URL)
+		c.RetryPolicy = &DefaultRetryPolicy{InitialInterval: time.Millisecond}
+		c.RetryTimeout = 0
+
+		assert.Nil(t, c.PostMetrics([]Metric{{Metric: String("foo.bar")}}))
+		assert.Equal(t, 2, *calls)
+	})
+}
@@ -0,0 +1,333 @@
+/*
+ * Datadog API for Go
+ *
+ * Please see the included LICENSE file for licensing information.
+ *
+ * Copyright 2020 by authors and contributors.
+ */
+
+package datadog
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SeriesPoint is a point of a Series returned by QueryMetrics. Value is nil
+// for a gap in the series.
+type SeriesPoint struct {
+	Time  time.Time
+	Value *float64
+}
+
+// SeriesPoints are the points of a series, in time order.
+type SeriesPoints []SeriesPoint
+
+// TimePoints returns the points of the series in time order. The timestamps
+// of the points returned by QueryMetrics are in milliseconds.
+func (s *Series) TimePoints() SeriesPoints {
+	points := make(SeriesPoints, 0, len(s.Points))
+	for _, point := range s.Points {
+		if point[0] == nil {
+			continue
+		}
+		p := SeriesPoint{Time: time.Unix(0, int64(*point[0])*int64(time.Millisecond))}
+		if point[1] != nil {
+			p.Value = Float64(*point[1])
+		}
+		points = append(points, p)
+	}
+	sort.SliceStable(points, func(i, j int) bool {
+		return points[i].Time.Before(points[j].Time)
+	})
+	return points
+}
+
+// ScopeTag is a tag of the scope of a series. Value is empty for a tag
+// without a value, e.g. "production".
+type ScopeTag struct {
+	Key   string
+	Value string
+}
+
+// String returns the tag as written in a scope.
+func (t ScopeTag) String() string {
+	if t.Value == "" {
+		return t.Key
+	}
+	return t.Key + ":" + t.Value
+}
+
+// ScopeTags returns the tags of the scope of the series, e.g. host:foo and
+// env:prod for "host:foo,env:prod". The scope "*", meaning everything, has
+// no tags.
+func (s *Series) ScopeTags() []ScopeTag {
+	var tags []ScopeTag
+	for _, tag := range strings.Split(s.GetScope(), ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || tag == "*" {
+			continue
+		}
+		parts := strings.SplitN(tag, ":", 2)
+		scopeTag := ScopeTag{Key: parts[0]}
+		if len(parts) == 2 {
+			scopeTag.Value = parts[1]
+		}
+		tags = append(tags, scopeTag)
+	}
+	return tags
+}
+
+// ScopeTagValues returns the values of the tags of the scope of the series
+// by key, e.g. {"host": "foo", "env": "prod"} for "host:foo,env:prod".
+func (s *Series) ScopeTagValues() map[string]string {
+	values := make(map[string]string)
+	for _, tag := range s.ScopeTags() {
+		values[tag.Key] = tag.Value
+	}
+	return values
+}
+
+// Values returns the values of the points, leaving out the gaps.
+func (p SeriesPoints) Values() []float64 {
+	values := make([]float64, 0, len(p))
+	for _, point := range p {
+		if point.Value != nil {
+			values = append(values, *point.Value)
+		}
+	}
+	return values
+}
+
+// maxSeriesPoints bounds the points Resample and FillGaps return, in case of
+// an interval much smaller than the time the series spans.
+const maxSeriesPoints = 1000000
+
+// SeriesAggregator is how Resample aggregates the values of a bucket.
+type SeriesAggregator string
+
+const (
+	// SeriesAvg averages the values.
+	SeriesAvg SeriesAggregator = "avg"
+	// SeriesSum sums the values.
+	SeriesSum SeriesAggregator = "sum"
+	// SeriesMin takes the smallest value.
+	SeriesMin SeriesAggregator = "min"
+	// SeriesMax takes the largest value.
+	SeriesMax SeriesAggregator = "max"
+)
+
+// Resample returns the points aggregated in buckets of interval, aligned on
+// the UNIX epoch. The points of buckets with no values are gaps, so that the
+// points are evenly spaced.
+func (p SeriesPoints) Resample(interval time.Duration, aggregator SeriesAggregator) (SeriesPoints, error) {
+	aggregate, ok := seriesAggregators[aggregator]
+	if !ok {
+		return nil, fmt.Errorf("unknown aggregator %q", aggregator)
+	}
+	if interval <= 0 {
+		return nil, fmt.Errorf("interval must be positive, not %s", interval)
+	}
+	if len(p) == 0 {
+		return nil, nil
+	}
+
+	bucket := func(t time.Time) int64 {
+		ns := t.UnixNano()
+		if ns < 0 {
+			ns -= int64(interval) - 1
+		}
+		return ns / int64(interval)
+	}
+	values := make(map[int64][]float64)
+	first, last := bucket(p[0].Time), bucket(p[0].Time)
+	for _, point := range p {
+		b := bucket(point.Time)
+		if b < first {
+			first = b
+		}
+		if b > last {
+			last = b
+		}
+		if point.Value != nil {
+			values[b] = append(values[b], *point.Value)
+		}
+	}
+
+	if last-first >= maxSeriesPoints {
+		return nil, fmt.Errorf("interval %s makes more than %d points", interval, maxSeriesPoints)
+	}
+
+	out := make(SeriesPoints, 0, last-first+1)
+	for b := first; b <= last; b++ {
+		point := SeriesPoint{Time: time.Unix(0, b*int64(interval))}
+		if len(values[b]) > 0 {
+			point.Value = Float64(aggregate(values[b]))
+		}
+		out = append(out, point)
+	}
+	return out, nil
+}
+
+// seriesAggregators are the aggregators of Resample.
+var seriesAggregators = map[SeriesAggregator]func([]float64) float64{
+	SeriesAvg: func(values []float64) float64 { return sumValues(values) / float64(len(values)) },
+	SeriesSum: sumValues,
+	SeriesMin: func(values []float64) float64 {
+		min := values[0]
+		for _, value := range values {
+			min = math.Min(min, value)
+		}
+		return min
+	},
+	SeriesMax: func(values []float64) float64 {
+		max := values[0]
+		for _, value := range values {
+			max = math.Max(max, value)
+		}
+		return max
+	},
+}
+
+func sumValues(values []float64) float64 {
+	sum := 0.0
+	for _, value := range values {
+		sum += value
+	}
+	return sum
+}
+
+// FillMethod is how FillGaps fills the gaps of a series, like the fill()
+// function of the query language.
+type FillMethod string
+
+const (
+	// FillZero fills gaps with 0.
+	FillZero FillMethod = "zero"
+	// FillLast fills gaps with the last value before them. Gaps at the start
+	// of the series are left as they are.
+	FillLast FillMethod = "last"
+	// FillLinear interpolates gaps between the values around them. Gaps at
+	// the start or the end of the series are left as they are.
+	FillLinear FillMethod = "linear"
+)
+
+// FillGaps returns the points with their gaps filled. interval is the time
+// between the points of the series: the points missing between two points
+// further apart than interval are added as gaps first, e.g. for a series
+// whose gaps were left out rather than returned as nil values.
+func (p SeriesPoints) FillGaps(interval time.Duration, method FillMethod) (SeriesPoints, error) {
+	if method != FillZero && method != FillLast && method != FillLinear {
+		return nil, fmt.Errorf("unknown fill method %q", method)
+	}
+	if interval <= 0 {
+		return nil, fmt.Errorf("interval must be positive, not %s", interval)
+	}
+	count := len(p)
+	for i := 1; i < len(p); i++ {
+		if count += int(p[i].Time.Sub(p[i-1].Time) / interval); count > maxSeriesPoints {
+			return nil, fmt.Errorf("interval %s makes more than %d points", interval, maxSeriesPoints)
+		}
+	}
+
+	out := make(SeriesPoints, 0, count)
+	for i, point := range p {
+		if i > 0 {
+			for t := p[i-1].Time.Add(interval); t.Before(point.Time); t = t.Add(interval) {
+				out = append(out, SeriesPoint{Time: t})
+			}
+		}
+		out = append(out, point)
+	}
+
+	previous := -1
+	for i, point := range out {
+		if point.Value != nil {
+			if method == FillLinear && previous >= 0 && previous < i-1 {
+				from, to := out[previous], point
+				span := float64(to.Time.Sub(from.Time))
+				for j := previous + 1; j < i; j++ {
+					ratio := float64(out[j].Time.Sub(from.Time)) / span
+					out[j].Value = Float64(*from.Value + ratio*(*to.Value-*from.Value))
+				}
+			}
+			previous = i
+			continue
+		}
+		switch {
+		case method == FillZero:
+			out[i].Value = Float64(0)
+		case method == FillLast && previous >= 0:
+			out[i].Value = Float64(*out[previous].Value)
+		}
+	}
+	return out, nil
+}
+
+// SeriesStats are summary statistics of the values of a series, gaps left
+// out. They are all 0 if the series has no values.
+type SeriesStats struct {
+	Count int
+	Min   float64
+	Max   float64
+	Avg   float64
+	P95   float64
+}
+
+// Stats returns summary statistics of the values of the points.
+func (p SeriesPoints) Stats() SeriesStats {
+	values := p.Values()
+	if len(values) == 0 {
+		return SeriesStats{}
+	}
+	sort.Float64s(values)
+	return SeriesStats{
+		Count: len(values),
+		Min:   values[0],
+		Max:   values[len(values)-1],
+		Avg:   sumValues(values) / float64(len(values)),
+		P95:   percentile(values, 0.95),
+	}
+}
+
+// JoinedPoint is a point in time of series joined by JoinSeries. Values
+// holds the value of every series at Time, in the order the series were
+// given, nil if a series has no value then.
+type JoinedPoint struct {
+	Time   time.Time
+	Values []*float64
+}
+
+// JoinSeries lines up the points of several series on their timestamps, for
+// side by side analysis. It returns a point for every time any series has a
+// point at, in time order.
+func JoinSeries(series ...SeriesPoints) []JoinedPoint {
+	byTime := make(map[int64]*JoinedPoint)
+	var joined []*JoinedPoint
+	for i, points := range series {
+		for _, point := range points {
+			key := point.Time.UnixNano()
+			row, ok := byTime[key]
+			if !ok {
+				row = &JoinedPoint{Time: point.Time, Values: make([]*float64, len(series))}
+				byTime[key] = row
+				joined = append(joined, row)
+			}
+			if point.Value != nil {
+				row.Values[i] = Float64(*point.Value)
+			}
+		}
+	}
+	sort.Slice(joined, func(i, j int) bool {
+		return joined[i].Time.Before(joined[j].Time)
+	})
+
+	out := make([]JoinedPoint, len(joined))
+	for i, row := range joined {
+		out[i] = *row
+	}
+	return out
+}
@@ -0,0 +1,136 @@
+package datadog_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	dd "github.com/zorkian/go-datadog-api"
+)
+
+// seriesPoints builds points every minute from values, nil values being gaps.
+func seriesPoints(values ...*float64) dd.SeriesPoints {
+	points := make(dd.SeriesPoints, len(values))
+	for i, value := range values {
+		points[i] = dd.SeriesPoint{Time: time.Unix(int64(60*i), 0), Value: value}
+	}
+	return points
+}
+
+// pointValues returns the values of points, nil for gaps.
+func pointValues(points dd.SeriesPoints) []interface{} {
+	values := make([]interface{}, len(points))
+	for i, point := range points {
+		if point.Value != nil {
+			values[i] = *point.Value
+		}
+	}
+	return values
+}
+
+func TestSeriesTimePoints(t *testing.T) {
+	series := dd.Series{Points: []dd.DataPoint{
+		{dd.Float64(1600000060000), nil},
+		{dd.Float64(1600000000000), dd.Float64(2)},
+	}}
+	points := series.TimePoints()
+	assert.Len(t, points, 2)
+	assert.Equal(t, time.Unix(1600000000, 0), points[0].Time)
+	assert.Equal(t, 2.0, *points[0].Value)
+	assert.Nil(t, points[1].Value)
+}
+
+func TestSeriesScopeTags(t *testing.T) {
+	series := dd.Series{Scope: dd.String("host:foo, env:prod,url:http://x,production")}
+	assert.Equal(t, []dd.ScopeTag{
+		{Key: "host", Value: "foo"},
+		{Key: "env", Value: "prod"},
+		{Key: "url", Value: "http://x"},
+		{Key: "production"},
+	}, series.ScopeTags())
+	assert.Equal(t, "prod", series.ScopeTagValues()["env"])
+	assert.Equal(t, "url:http://x", series.ScopeTags()[2].String())
+
+	series.Scope = dd.String("*")
+	assert.Empty(t, series.ScopeTags())
+}
+
+func TestSeriesResample(t *testing.T) {
+	points := seriesPoints(dd.Float64(1), dd.Float64(3), nil, nil, dd.Float64(5), dd.Float64(8))
+
+	resampled, err := points.Resample(2*time.Minute, dd.SeriesAvg)
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{2.0, nil, 6.5}, pointValues(resampled))
+	assert.Equal(t, time.Unix(240, 0), resampled[2].Time)
+
+	resampled, err = points.Resample(3*time.Minute, dd.SeriesMax)
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{3.0, 8.0}, pointValues(resampled))
+
+	_, err = points.Resample(time.Minute, "median")
+	assert.NotNil(t, err)
+	_, err = points.Resample(0, dd.SeriesSum)
+	assert.NotNil(t, err)
+	_, err = points.Resample(time.Nanosecond, dd.SeriesSum)
+	assert.NotNil(t, err)
+}
+
+func TestSeriesFillGaps(t *testing.T) {
+	points := seriesPoints(nil, dd.Float64(1), nil, nil, dd.Float64(4), nil)
+
+	filled, err := points.FillGaps(time.Minute, dd.FillZero)
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{0.0, 1.0, 0.0, 0.0, 4.0, 0.0}, pointValues(filled))
+
+	filled, err = points.FillGaps(time.Minute, dd.FillLast)
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{nil, 1.0, 1.0, 1.0, 4.0, 4.0}, pointValues(filled))
+
+	filled, err = points.FillGaps(time.Minute, dd.FillLinear)
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{nil, 1.0, 2.0, 3.0, 4.0, nil}, pointValues(filled))
+	// The points given are left as they are.
+	assert.Nil(t, points[2].Value)
+
+	// Points left out of the series are added back.
+	sparse := dd.SeriesPoints{points[1], points[4]}
+	filled, err = sparse.FillGaps(time.Minute, dd.FillLinear)
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{1.0, 2.0, 3.0, 4.0}, pointValues(filled))
+	assert.Equal(t, time.Unix(180, 0), filled[2].Time)
+
+	_, err = points.FillGaps(time.Minute, "null")
+	assert.NotNil(t, err)
+	_, err = points.FillGaps(0, dd.FillZero)
+	assert.NotNil(t, err)
+	// Intervals much smaller than the series make too many points.
+	_, err = sparse.FillGaps(time.Nanosecond, dd.FillZero)
+	assert.NotNil(t, err)
+}
+
+func TestSeriesStats(t *testing.T) {
+	values := make([]*float64, 0, 21)
+	for i := 20; i >= 1; i-- {
+		values = append(values, dd.Float64(float64(i)))
+	}
+	values = append(values, nil)
+
+	stats := seriesPoints(values...).Stats()
+	assert.Equal(t, dd.SeriesStats{Count: 20, Min: 1, Max: 20, Avg: 10.5, P95: 19}, stats)
+	assert.Equal(t, dd.SeriesStats{}, seriesPoints(nil).Stats())
+}
+
+func TestJoinSeries(t *testing.T) {
+	a := seriesPoints(dd.Float64(1), dd.Float64(2))
+	b := dd.SeriesPoints{
+		{Time: time.Unix(60, 0), Value: dd.Float64(20)},
+		{Time: time.Unix(120, 0), Value: dd.Float64(30)},
+	}
+
+	joined := dd.JoinSeries(a, b)
+	assert.Len(t, joined, 3)
+	assert.Equal(t, time.Unix(0, 0), joined[0].Time)
+	assert.Equal(t, []*float64{dd.Float64(1), nil}, joined[0].Values)
+	assert.Equal(t, []*float64{dd.Float64(2), dd.Float64(20)}, joined[1].Values)
+	assert.Equal(t, []*float64{nil, dd.Float64(30)}, joined[2].Values)
+}
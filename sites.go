@@ -0,0 +1,97 @@
+/*
+ * Datadog API for Go
+ *
+ * Please see the included LICENSE file for licensing information.
+ *
+ * Copyright 2020 by authors and contributors.
+ */
+
+package datadog
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Datadog sites, see https://docs.datadoghq.com/getting_started/site/
+const (
+	SiteUS1    = "datadoghq.com"
+	SiteUS3    = "us3.datadoghq.com"
+	SiteUS5    = "us5.datadoghq.com"
+	SiteEU1    = "datadoghq.eu"
+	SiteAP1    = "ap1.datadoghq.com"
+	SiteUS1FED = "ddog-gov.com"
+)
+
+// Site holds the URLs a Datadog site serves its API, its logs intake and its
+// IP ranges on.
+type Site struct {
+	Name          string
+	APIURL        string
+	LogsIntakeURL string
+	IPRangesURL   string
+}
+
+// Sites lists the Datadog sites known to the client.
+var Sites = []Site{
+	NewSite(SiteUS1),
+	NewSite(SiteUS3),
+	NewSite(SiteUS5),
+	NewSite(SiteEU1),
+	NewSite(SiteAP1),
+	NewSite(SiteUS1FED),
+}
+
+// NewSite returns the URLs of the Datadog site with the given name, such as
+// "us3.datadoghq.com". All sites follow the same naming scheme, so it works
+// for sites missing from Sites as well.
+func NewSite(name string) Site {
+	return Site{
+		Name:          name,
+		APIURL:        "https://api." + name,
+		LogsIntakeURL: "https://http-intake.logs." + name,
+		IPRangesURL:   "https://ip-ranges." + name,
+	}
+}
+
+// siteForURL returns the Datadog site serving baseURL, e.g. the US3 site for
+// "https://api.us3.datadoghq.com" or "https://us3.datadoghq.com".
+func siteForURL(baseURL string) (Site, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return Site{}, err
+	}
+	host := u.Hostname()
+
+	// Several site names are suffixes of each other, the longest one wins.
+	var site Site
+	for _, s := range Sites {
+		if (host == s.Name || strings.HasSuffix(host, "."+s.Name)) && len(s.Name) > len(site.Name) {
+			site = s
+		}
+	}
+	if site.Name != "" {
+		return site, nil
+	}
+	if strings.HasPrefix(host, "api.") {
+		return NewSite(strings.TrimPrefix(host, "api.")), nil
+	}
+	return Site{}, fmt.Errorf("unknown Datadog site for %s", baseURL)
+}
+
+// GetSite returns the Datadog site the client talks to, as found from its base
+// URL.
+func (client *Client) GetSite() (Site, error) {
+	return siteForURL(client.GetBaseUrl())
+}
+
+// URLLogsIntake returns the URL of the HTTP intake logs are sent to for the
+// Datadog site of the client.
+func (client *Client) URLLogsIntake() (string, error) {
+	site, err := client.GetSite()
+	if err != nil {
+		return "", err
+	}
+	return site.LogsIntakeURL, nil
+}
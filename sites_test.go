@@ -0,0 +1,49 @@
+package datadog
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSiteForURL(t *testing.T) {
+	for _, tt := range []struct {
+		baseURL string
+		site    string
+	}{
+		{"https://api.datadoghq.com", SiteUS1},
+		{"https://app.datadoghq.com", SiteUS1},
+		{"https://api.datadoghq.eu", SiteEU1},
+		{"https://api.us3.datadoghq.com", SiteUS3},
+		{"https://us5.datadoghq.com", SiteUS5},
+		{"https://api.ap1.datadoghq.com/", SiteAP1},
+		{"https://api.ddog-gov.com", SiteUS1FED},
+		{"https://api.xx9.datadoghq.example", "xx9.datadoghq.example"},
+	} {
+		site, err := siteForURL(tt.baseURL)
+		assert.Nil(t, err)
+		assert.Equal(t, NewSite(tt.site), site, tt.baseURL)
+	}
+
+	_, err := siteForURL("http://127.0.0.1:8080")
+	assert.NotNil(t, err)
+}
+
+func TestSiteURLs(t *testing.T) {
+	for _, tt := range []struct {
+		site, ipRanges, logsIntake string
+	}{
+		{SiteUS1, "https://ip-ranges.datadoghq.com", "https://http-intake.logs.datadoghq.com"},
+		{SiteEU1, "https://ip-ranges.datadoghq.eu", "https://http-intake.logs.datadoghq.eu"},
+		{SiteUS3, "https://ip-ranges.us3.datadoghq.com", "https://http-intake.logs.us3.datadoghq.com"},
+		{SiteUS1FED, "https://ip-ranges.ddog-gov.com", "https://http-intake.logs.ddog-gov.com"},
+	} {
+		c := NewClientWithOptions(WithSite(tt.site))
+		ipRanges, err := c.URLIPRanges()
+		assert.Nil(t, err)
+		assert.Equal(t, tt.ipRanges, ipRanges)
+		logsIntake, err := c.URLLogsIntake()
+		assert.Nil(t, err)
+		assert.Equal(t, tt.logsIntake, logsIntake)
+	}
+}
@@ -1,6 +1,7 @@
 package datadog
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/url"
 )
@@ -24,6 +25,27 @@ type SyntheticsTest struct {
 	ModifiedBy    *SyntheticsUser    `json:"modified_by,omitempty"`
 	Status        *string            `json:"status,omitempty"`
 	MonitorStatus *string            `json:"monitor_status,omitempty"`
+
+	// unknown holds the fields this library does not know, in lossless mode.
+	unknown map[string]json.RawMessage
+}
+
+func (s *SyntheticsTest) setUnknownFields(unknown map[string]json.RawMessage) {
+	s.unknown = unknown
+}
+
+func (s *SyntheticsTest) readOnlyFields() []string {
+	return []string{"created_at", "modified_at", "created_by", "modified_by", "creator", "deleted_at", "monitor_id", "monitor_status", "public_id"}
+}
+
+// MarshalJSON encodes the synthetics test, including the fields kept in lossless mode.
+func (s SyntheticsTest) MarshalJSON() ([]byte, error) {
+	type syntheticsTest SyntheticsTest
+	data, err := json.Marshal(syntheticsTest(s))
+	if err != nil {
+		return nil, err
+	}
+	return addUnknownFields(data, s.unknown)
 }
 
 type SyntheticsConfig struct {
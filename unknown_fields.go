@@ -0,0 +1,206 @@
+/*
+ * Datadog API for Go
+ *
+ * Please see the included LICENSE file for licensing information.
+ *
+ * Copyright 2020 by authors and contributors.
+ */
+
+package datadog
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// knownFieldsCache maps a struct type to the set of JSON keys it decodes.
+var knownFieldsCache sync.Map
+
+// knownFields returns the lower-cased JSON keys of the exported fields of the
+// struct type t. Keys are lower-cased because encoding/json matches them
+// case-insensitively.
+func knownFields(t reflect.Type) map[string]bool {
+	if known, ok := knownFieldsCache.Load(t); ok {
+		return known.(map[string]bool)
+	}
+
+	known := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if field.Anonymous && tag == "" && field.Type.Kind() == reflect.Struct {
+			for key := range knownFields(field.Type) {
+				known[key] = true
+			}
+			continue
+		}
+		if name, ok := jsonFieldName(field); ok {
+			known[strings.ToLower(name)] = true
+		}
+	}
+
+	knownFieldsCache.Store(t, known)
+	return known
+}
+
+// jsonFieldName returns the JSON key of a struct field, or false if the field
+// is not encoded.
+func jsonFieldName(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("json")
+	if field.PkgPath != "" || tag == "-" {
+		return "", false
+	}
+	if name := strings.Split(tag, ",")[0]; name != "" {
+		return name, true
+	}
+	return field.Name, true
+}
+
+// unknownFields returns the members of the JSON object data that do not
+// decode into a field of the struct type t, or nil if there are none.
+func unknownFields(data []byte, t reflect.Type) (map[string]json.RawMessage, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	known := knownFields(t)
+	for key := range raw {
+		if known[strings.ToLower(key)] {
+			delete(raw, key)
+		}
+	}
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	return raw, nil
+}
+
+// addUnknownFields adds the members of unknown to the JSON object data,
+// unless data already has them.
+func addUnknownFields(data []byte, unknown map[string]json.RawMessage) ([]byte, error) {
+	if len(unknown) == 0 {
+		return data, nil
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	for key, value := range unknown {
+		if _, ok := raw[key]; !ok {
+			raw[key] = value
+		}
+	}
+	return json.Marshal(raw)
+}
+
+// withoutFields returns fields without the given keys, matched
+// case-insensitively as encoding/json does, or nil if none are left.
+func withoutFields(fields map[string]json.RawMessage, keys []string) map[string]json.RawMessage {
+	for key := range fields {
+		for _, k := range keys {
+			if strings.EqualFold(key, k) {
+				delete(fields, key)
+				break
+			}
+		}
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	return fields
+}
+
+// WithLossless sets whether resources keep the JSON fields this library does
+// not know, see Client.Lossless.
+func WithLossless(lossless bool) ClientOption {
+	return func(c *Client) {
+		c.Lossless = lossless
+	}
+}
+
+// unknownFieldsKeeper is implemented by the resources that keep the JSON
+// fields this library does not know in lossless mode.
+type unknownFieldsKeeper interface {
+	setUnknownFields(unknown map[string]json.RawMessage)
+	// readOnlyFields returns the JSON keys of the fields of the resource that
+	// are set by the API, such as creation times. They are never kept, so
+	// that updates do not try to overwrite them.
+	readOnlyFields() []string
+}
+
+var unknownFieldsKeeperType = reflect.TypeOf((*unknownFieldsKeeper)(nil)).Elem()
+
+// keepUnknownFields walks v, which was decoded from data, and gives every
+// unknownFieldsKeeper found the fields of data it did not decode. Resources
+// are found at any depth, e.g. in the slices of list responses.
+func keepUnknownFields(v reflect.Value, data json.RawMessage) error {
+	if len(data) == 0 || string(data) == "null" {
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return keepUnknownFields(v.Elem(), data)
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return nil
+		}
+		var items []json.RawMessage
+		if err := json.Unmarshal(data, &items); err != nil {
+			return nil
+		}
+		for i := 0; i < v.Len() && i < len(items); i++ {
+			if err := keepUnknownFields(v.Index(i), items[i]); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		if v.CanAddr() && v.Addr().Type().Implements(unknownFieldsKeeperType) {
+			unknown, err := unknownFields(data, v.Type())
+			if err != nil {
+				return err
+			}
+			keeper := v.Addr().Interface().(unknownFieldsKeeper)
+			keeper.setUnknownFields(withoutFields(unknown, keeper.readOnlyFields()))
+		}
+		var members map[string]json.RawMessage
+		if err := json.Unmarshal(data, &members); err != nil {
+			return nil
+		}
+		return keepStructUnknownFields(v, members)
+	}
+	return nil
+}
+
+// keepStructUnknownFields calls keepUnknownFields on the fields of the struct
+// v, with the members of its JSON object.
+func keepStructUnknownFields(v reflect.Value, members map[string]json.RawMessage) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if field.Anonymous && tag == "" && field.Type.Kind() == reflect.Struct {
+			if err := keepStructUnknownFields(v.Field(i), members); err != nil {
+				return err
+			}
+			continue
+		}
+		name, ok := jsonFieldName(field)
+		if !ok {
+			continue
+		}
+		for key, member := range members {
+			if strings.EqualFold(key, name) {
+				if err := keepUnknownFields(v.Field(i), member); err != nil {
+					return err
+				}
+				break
+			}
+		}
+	}
+	return nil
+}
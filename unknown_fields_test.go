@@ -0,0 +1,88 @@
+package datadog_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	dd "github.com/zorkian/go-datadog-api"
+)
+
+func TestLosslessRoundTrip(t *testing.T) {
+	var updated map[string]json.RawMessage
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/api/v1/monitor/1":
+			w.Write([]byte(`{"id": 1, "name": "foo", "type": "metric alert", "tags": [],
+				"draft_status": "published", "options": {"notify_audit": true},
+				"created": "2020-01-01T00:00:00.000000+00:00", "modified": "2020-01-02T00:00:00.000000+00:00",
+				"org_id": 2, "deleted": null}`))
+		case r.Method == "GET" && r.URL.Path == "/api/v1/downtime":
+			w.Write([]byte(`[{"id": 1, "mute_first_recovery_notification": true}, {"id": 2}]`))
+		case r.Method == "PUT":
+			body, _ := ioutil.ReadAll(r.Body)
+			updated = nil
+			assert.Nil(t, json.Unmarshal(body, &updated))
+			w.Write(body)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+	}))
+	defer ts.Close()
+
+	client := dd.NewClientWithOptions(dd.WithKeys("foo", "bar"), dd.WithBaseUrl(ts.URL))
+
+	// Unknown fields are dropped by default.
+	monitor, err := client.GetMonitor(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Nil(t, client.UpdateMonitor(monitor))
+	assert.NotContains(t, updated, "draft_status")
+
+	client.Lossless = true
+	monitor, err = client.GetMonitor(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	monitor.SetName("bar")
+	assert.Nil(t, client.UpdateMonitor(monitor))
+	assert.Equal(t, `"published"`, string(updated["draft_status"]))
+	assert.Equal(t, `"bar"`, string(updated["name"]))
+	// Fields set by the API are not sent back.
+	for _, key := range []string{"created", "modified", "org_id", "deleted"} {
+		assert.NotContains(t, updated, key)
+	}
+
+	// Resources in lists keep their unknown fields too.
+	downtimes, err := client.GetDowntimes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Nil(t, client.UpdateDowntime(&downtimes[0]))
+	assert.Equal(t, `true`, string(updated["mute_first_recovery_notification"]))
+	assert.Nil(t, client.UpdateDowntime(&downtimes[1]))
+	assert.NotContains(t, updated, "mute_first_recovery_notification")
+}
+
+func TestLosslessBoard(t *testing.T) {
+	raw := `{"title": "foo", "widgets": [], "layout_type": "ordered", "reflow_type": "fixed"}`
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(raw))
+	}))
+	defer ts.Close()
+
+	client := dd.NewClientWithOptions(dd.WithKeys("foo", "bar"), dd.WithBaseUrl(ts.URL), dd.WithLossless(true))
+	board, err := client.GetBoard("abc-def-ghi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(board)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.JSONEq(t, raw, string(data))
+}